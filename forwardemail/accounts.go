@@ -1,27 +1,67 @@
 package forwardemail
 
 import (
+	"context"
 	"encoding/json"
+	"net/url"
 	"time"
 )
 
 type Account struct {
-	Plan           string    `json:"plan"`
-	Email          string    `json:"email"`
-	FullEmail      string    `json:"full_email"`
-	DisplayName    string    `json:"display_name"`
-	LastLocale     string    `json:"last_locale"`
-	AddressCountry string    `json:"address_country"`
-	Id             string    `json:"id"`
-	Object         string    `json:"object"`
-	Locale         string    `json:"locale"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
-	AddressHtml    string    `json:"address_html"`
+	Plan                 string    `json:"plan"`
+	PlanExpiresAt        time.Time `json:"plan_expires_at"`
+	Email                string    `json:"email"`
+	FullEmail            string    `json:"full_email"`
+	DisplayName          string    `json:"display_name"`
+	GivenName            string    `json:"given_name"`
+	FamilyName           string    `json:"family_name"`
+	AvatarUrl            string    `json:"avatar_url"`
+	LastLocale           string    `json:"last_locale"`
+	AddressLine1         string    `json:"address_line1"`
+	AddressLine2         string    `json:"address_line2"`
+	AddressCity          string    `json:"address_city"`
+	AddressState         string    `json:"address_state"`
+	AddressZip           string    `json:"address_zip"`
+	AddressCountry       string    `json:"address_country"`
+	CompanyName          string    `json:"company_name"`
+	CompanyVat           string    `json:"company_vat"`
+	MaxQuotaPerAlias     int64     `json:"max_quota_per_alias"`
+	StorageUsed          int64     `json:"storage_used"`
+	StorageUsedByAliases int64     `json:"storage_used_by_aliases"`
+	StorageQuota         int64     `json:"storage_quota"`
+	Id                   string    `json:"id"`
+	Object               string    `json:"object"`
+	Locale               string    `json:"locale"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+	AddressHtml          string    `json:"address_html"`
+}
+
+// AccountParameters holds the account profile fields to change. The display
+// name is derived by the API from GivenName and FamilyName.
+type AccountParameters struct {
+	Email          *string
+	GivenName      *string
+	FamilyName     *string
+	AvatarUrl      *string
+	Locale         *string
+	AddressLine1   *string
+	AddressLine2   *string
+	AddressCity    *string
+	AddressState   *string
+	AddressZip     *string
+	AddressCountry *string
+	CompanyName    *string
+	CompanyVat     *string
 }
 
 func (c *Client) GetAccount() (*Account, error) {
-	req, err := c.newRequest("GET", "/v1/account")
+	return c.GetAccountContext(context.Background())
+}
+
+// GetAccountContext is like GetAccount but carries ctx with the request.
+func (c *Client) GetAccountContext(ctx context.Context) (*Account, error) {
+	req, err := c.newRequest(ctx, "GET", "/v1/account")
 	if err != nil {
 		return nil, err
 	}
@@ -40,3 +80,53 @@ func (c *Client) GetAccount() (*Account, error) {
 
 	return &item, nil
 }
+
+func (c *Client) UpdateAccount(parameters AccountParameters) (*Account, error) {
+	return c.UpdateAccountContext(context.Background(), parameters)
+}
+
+// UpdateAccountContext is like UpdateAccount but carries ctx with the request.
+func (c *Client) UpdateAccountContext(ctx context.Context, parameters AccountParameters) (*Account, error) {
+	req, err := c.newRequest(ctx, "PUT", "/v1/account")
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+
+	for k, v := range map[string]*string{
+		"email":           parameters.Email,
+		"given_name":      parameters.GivenName,
+		"family_name":     parameters.FamilyName,
+		"avatar_url":      parameters.AvatarUrl,
+		"locale":          parameters.Locale,
+		"address_line1":   parameters.AddressLine1,
+		"address_line2":   parameters.AddressLine2,
+		"address_city":    parameters.AddressCity,
+		"address_state":   parameters.AddressState,
+		"address_zip":     parameters.AddressZip,
+		"address_country": parameters.AddressCountry,
+		"company_name":    parameters.CompanyName,
+		"company_vat":     parameters.CompanyVat,
+	} {
+		if v != nil {
+			params.Add(k, *v)
+		}
+	}
+
+	setFormBody(req, params)
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item Account
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -33,27 +34,135 @@ func TestClient_GetAccount(t *testing.T) {
   				"locale": "en",
   				"created_at": "2023-09-21T20:14:27.964Z",
   				"updated_at": "2023-10-07T17:47:54.595Z",
-  				"address_html": ""
+  				"address_html": "",
+  				"max_quota_per_alias": 10737418240,
+  				"storage_used": 1073741824,
+  				"storage_used_by_aliases": 536870912,
+  				"storage_quota": 10737418240,
+  				"plan_expires_at": "2024-09-21T20:14:27.964Z"
 			}`,
+			want: &Account{
+				Plan:                 "enhanced_protection",
+				Email:                "tony@stark.com",
+				FullEmail:            "tony@stark.com",
+				DisplayName:          "tony@stark.com",
+				LastLocale:           "en",
+				AddressCountry:       "None",
+				Id:                   "59ad551ae6fb4a4c53427ca38079f029",
+				Object:               "user",
+				Locale:               "en",
+				CreatedAt:            parseTime("2023-09-21T20:14:27.964Z"),
+				UpdatedAt:            parseTime("2023-10-07T17:47:54.595Z"),
+				MaxQuotaPerAlias:     10737418240,
+				StorageUsed:          1073741824,
+				StorageUsedByAliases: 536870912,
+				StorageQuota:         10737418240,
+				PlanExpiresAt:        parseTime("2024-09-21T20:14:27.964Z"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got, _ := c.GetAccount()
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_UpdateAccount(t *testing.T) {
+	tests := []struct {
+		name       string
+		parameters AccountParameters
+		response   string
+		wantForm   url.Values
+		want       *Account
+	}{
+		{
+			name:     "no data",
+			wantForm: url.Values{},
+		},
+		{
+			name: "ok",
+			parameters: AccountParameters{
+				Email:          pointString("tony@starkindustries.com"),
+				GivenName:      pointString("Tony"),
+				FamilyName:     pointString("Stark"),
+				Locale:         pointString("it"),
+				AddressLine1:   pointString("10880 Malibu Point"),
+				AddressCity:    pointString("Malibu"),
+				AddressState:   pointString("CA"),
+				AddressZip:     pointString("90265"),
+				AddressCountry: pointString("US"),
+			},
+			response: `{
+  				"plan": "enhanced_protection",
+  				"email": "tony@starkindustries.com",
+  				"full_email": "tony@starkindustries.com",
+  				"display_name": "Tony Stark",
+  				"given_name": "Tony",
+  				"family_name": "Stark",
+  				"address_line1": "10880 Malibu Point",
+  				"address_city": "Malibu",
+  				"address_state": "CA",
+  				"address_zip": "90265",
+  				"address_country": "US",
+  				"id": "59ad551ae6fb4a4c53427ca38079f029",
+  				"object": "user",
+  				"locale": "it",
+  				"created_at": "2023-09-21T20:14:27.964Z",
+  				"updated_at": "2023-10-08T10:01:12.100Z"
+			}`,
+			wantForm: url.Values{
+				"email":           {"tony@starkindustries.com"},
+				"given_name":      {"Tony"},
+				"family_name":     {"Stark"},
+				"locale":          {"it"},
+				"address_line1":   {"10880 Malibu Point"},
+				"address_city":    {"Malibu"},
+				"address_state":   {"CA"},
+				"address_zip":     {"90265"},
+				"address_country": {"US"},
+			},
 			want: &Account{
 				Plan:           "enhanced_protection",
-				Email:          "tony@stark.com",
-				FullEmail:      "tony@stark.com",
-				DisplayName:    "tony@stark.com",
-				LastLocale:     "en",
-				AddressCountry: "None",
+				Email:          "tony@starkindustries.com",
+				FullEmail:      "tony@starkindustries.com",
+				DisplayName:    "Tony Stark",
+				GivenName:      "Tony",
+				FamilyName:     "Stark",
+				AddressLine1:   "10880 Malibu Point",
+				AddressCity:    "Malibu",
+				AddressState:   "CA",
+				AddressZip:     "90265",
+				AddressCountry: "US",
 				Id:             "59ad551ae6fb4a4c53427ca38079f029",
 				Object:         "user",
-				Locale:         "en",
+				Locale:         "it",
 				CreatedAt:      parseTime("2023-09-21T20:14:27.964Z"),
-				UpdatedAt:      parseTime("2023-10-07T17:47:54.595Z"),
+				UpdatedAt:      parseTime("2023-10-08T10:01:12.100Z"),
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			var gotForm url.Values
+
 			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				gotForm = r.PostForm
 				fmt.Fprintf(w, tt.response)
 			}))
 			defer svr.Close()
@@ -62,10 +171,13 @@ func TestClient_GetAccount(t *testing.T) {
 				ApiUrl: svr.URL,
 			})
 
-			got, _ := c.GetAccount()
+			got, _ := c.UpdateAccount(tt.parameters)
 			if diff := cmp.Diff(tt.want, got); diff != "" {
 				t.Fatalf("values are not the same %s", diff)
 			}
+			if diff := cmp.Diff(tt.wantForm, gotForm); diff != "" {
+				t.Fatalf("form values are not the same %s", diff)
+			}
 		})
 	}
 }
@@ -1,38 +1,108 @@
 package forwardemail
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
+	"iter"
 	"net/url"
 	"strconv"
-	"strings"
 	"time"
 )
 
+// Error codes returned to senders when an alias is disabled.
+const (
+	AliasErrorCodeSilentDrop = 250
+	AliasErrorCodeSoftReject = 421
+	AliasErrorCodeHardReject = 550
+)
+
 type Alias struct {
-	Account                  Account   `json:"user"`
-	Domain                   Domain    `json:"domain"`
-	Name                     string    `json:"name"`
-	Labels                   []string  `json:"labels"`
-	IsEnabled                bool      `json:"is_enabled"`
-	HasRecipientVerification bool      `json:"has_recipient_verification"`
-	Recipients               []string  `json:"recipients"`
-	Id                       string    `json:"id"`
-	Object                   string    `json:"object"`
-	CreatedAt                time.Time `json:"created_at"`
-	UpdatedAt                time.Time `json:"updated_at"`
+	Account                  Account                `json:"user"`
+	Domain                   Domain                 `json:"domain"`
+	Name                     string                 `json:"name"`
+	Description              string                 `json:"description"`
+	Labels                   []string               `json:"labels"`
+	IsEnabled                bool                   `json:"is_enabled"`
+	ErrorCodeIfDisabled      int                    `json:"error_code_if_disabled"`
+	HasRecipientVerification bool                   `json:"has_recipient_verification"`
+	HasImap                  bool                   `json:"has_imap"`
+	HasPgp                   bool                   `json:"has_pgp"`
+	PublicKey                string                 `json:"public_key"`
+	MaxQuota                 int64                  `json:"max_quota"`
+	StorageUsed              int64                  `json:"storage_used"`
+	Retention                int                    `json:"retention"`
+	VacationResponder        AliasVacationResponder `json:"vacation_responder"`
+	Recipients               []string               `json:"recipients"`
+	PendingRecipients        []string               `json:"pending_recipients"`
+	VerifiedRecipients       []string               `json:"verified_recipients"`
+	Id                       string                 `json:"id"`
+	Object                   string                 `json:"object"`
+	CreatedAt                time.Time              `json:"created_at"`
+	UpdatedAt                time.Time              `json:"updated_at"`
+}
+
+type AliasVacationResponder struct {
+	IsEnabled bool      `json:"is_enabled"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	Subject   string    `json:"subject"`
+	Message   string    `json:"message"`
+}
+
+// ListAliasesOptions filters, sorts and selects a page of aliases. Zero
+// values use the API defaults.
+type ListAliasesOptions struct {
+	Page  int
+	Limit int
+
+	Name      string
+	Recipient string
+	// Q searches names, recipients, labels and descriptions.
+	Q         string
+	IsEnabled *bool
+	Labels    []string
+	// Sort is a field name such as "created_at", prefixed with "-" for
+	// descending order.
+	Sort string
 }
 
 type AliasParameters struct {
-	Recipients               *[]string
+	Description *string
+	// Recipients replaces the forwarding addresses. An empty list is not
+	// sent, since the API rejects aliases without recipients unless they
+	// are mailboxes, and those keep the recipients they already have.
+	Recipients *[]string
+	// Labels replaces the labels. An empty list clears them.
 	Labels                   *[]string
 	HasRecipientVerification *bool
 	IsEnabled                *bool
+	ErrorCodeIfDisabled      *int
+	HasImap                  *bool
+	HasPgp                   *bool
+	PublicKey                *string
+	// MaxQuota is a size in bytes or a human readable one such as "1 GB".
+	// An empty string resets it to the domain default.
+	MaxQuota          *string
+	Retention         *int
+	VacationResponder *AliasVacationResponderParameters
+}
+
+type AliasVacationResponderParameters struct {
+	IsEnabled *bool
+	StartDate *time.Time
+	EndDate   *time.Time
+	Subject   *string
+	Message   *string
 }
 
 func (c *Client) GetAliases(domain string) ([]Alias, error) {
-	req, err := c.newRequest("GET", fmt.Sprintf("/v1/domains/%s/aliases", domain))
+	return c.GetAliasesContext(context.Background(), domain)
+}
+
+// GetAliasesContext is like GetAliases but carries ctx with the request.
+func (c *Client) GetAliasesContext(ctx context.Context, domain string) ([]Alias, error) {
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("/v1/domains/%s/aliases", domain))
 	if err != nil {
 		return nil, err
 	}
@@ -52,8 +122,38 @@ func (c *Client) GetAliases(domain string) ([]Alias, error) {
 	return items, nil
 }
 
+// ListAliases returns a single page of the domain aliases together with the
+// total counts. Use AllAliases to go through every page.
+func (c *Client) ListAliases(domain string, options ListAliasesOptions) (*Page[Alias], error) {
+	return c.ListAliasesContext(context.Background(), domain, options)
+}
+
+// ListAliasesContext is like ListAliases but carries ctx with the request.
+func (c *Client) ListAliasesContext(ctx context.Context, domain string, options ListAliasesOptions) (*Page[Alias], error) {
+	return getPage[Alias](ctx, c, fmt.Sprintf("/v1/domains/%s/aliases", domain), options.encode())
+}
+
+// AllAliases iterates over the domain aliases, fetching pages lazily starting
+// from options.Page.
+func (c *Client) AllAliases(domain string, options ListAliasesOptions) iter.Seq2[Alias, error] {
+	return c.AllAliasesContext(context.Background(), domain, options)
+}
+
+// AllAliasesContext is like AllAliases but carries ctx with the request.
+func (c *Client) AllAliasesContext(ctx context.Context, domain string, options ListAliasesOptions) iter.Seq2[Alias, error] {
+	return all(options.Page, func(page int) (*Page[Alias], error) {
+		options.Page = page
+		return c.ListAliasesContext(ctx, domain, options)
+	})
+}
+
 func (c *Client) GetAlias(domain string, alias string) (*Alias, error) {
-	req, err := c.newRequest("GET", fmt.Sprintf("/v1/domains/%s/aliases/%s", domain, alias))
+	return c.GetAliasContext(context.Background(), domain, alias)
+}
+
+// GetAliasContext is like GetAlias but carries ctx with the request.
+func (c *Client) GetAliasContext(ctx context.Context, domain string, alias string) (*Alias, error) {
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("/v1/domains/%s/aliases/%s", domain, alias))
 	if err != nil {
 		return nil, err
 	}
@@ -74,36 +174,51 @@ func (c *Client) GetAlias(domain string, alias string) (*Alias, error) {
 }
 
 func (c *Client) CreateAlias(domain string, alias string, parameters AliasParameters) (*Alias, error) {
-	req, err := c.newRequest("POST", fmt.Sprintf("/v1/domains/%s/aliases", domain))
+	return c.CreateAliasContext(context.Background(), domain, alias, parameters)
+}
+
+// CreateAliasContext is like CreateAlias but carries ctx with the request.
+func (c *Client) CreateAliasContext(ctx context.Context, domain string, alias string, parameters AliasParameters) (*Alias, error) {
+	req, err := c.newRequest(ctx, "POST", fmt.Sprintf("/v1/domains/%s/aliases", domain))
 	if err != nil {
 		return nil, err
 	}
 
-	params := url.Values{}
+	params := parameters.encode()
 	params.Add("name", alias)
 
-	for k, v := range map[string]*bool{
-		"has_recipient_verification": parameters.HasRecipientVerification,
-		"is_enabled":                 parameters.IsEnabled,
-	} {
-		if v != nil {
-			params.Add(k, strconv.FormatBool(*v))
-		}
+	setFormBody(req, params)
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
 	}
 
-	for k, v := range map[string]*[]string{
-		"recipients[]": parameters.Recipients,
-		"labels[]":     parameters.Labels,
-	} {
-		if v != nil {
-			for _, vv := range *v {
-				params.Add(k, vv)
-			}
-		}
+	var item Alias
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) UpdateAlias(domain string, alias string, parameters AliasParameters) (*Alias, error) {
+	return c.UpdateAliasContext(context.Background(), domain, alias, parameters)
+}
+
+// UpdateAliasContext is like UpdateAlias but carries ctx with the request.
+func (c *Client) UpdateAliasContext(ctx context.Context, domain string, alias string, parameters AliasParameters) (*Alias, error) {
+	req, err := c.newRequest(ctx, "PUT", fmt.Sprintf("/v1/domains/%s/aliases/%s", domain, alias))
+	if err != nil {
+		return nil, err
 	}
 
-	req.Body = io.NopCloser(strings.NewReader(params.Encode()))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	params := parameters.encode()
+	params.Add("name", alias)
+
+	setFormBody(req, params)
 
 	res, err := c.doRequest(req)
 	if err != nil {
@@ -120,44 +235,83 @@ func (c *Client) CreateAlias(domain string, alias string, parameters AliasParame
 	return &item, nil
 }
 
-func (c *Client) UpdateAlias(domain string, alias string, parameters AliasParameters) (*Alias, error) {
-	req, err := c.newRequest("PUT", fmt.Sprintf("/v1/domains/%s/aliases/%s", domain, alias))
+func (c *Client) DeleteAlias(domain string, alias string) error {
+	return c.DeleteAliasContext(context.Background(), domain, alias)
+}
+
+// DeleteAliasContext is like DeleteAlias but carries ctx with the request.
+func (c *Client) DeleteAliasContext(ctx context.Context, domain string, alias string) error {
+	req, err := c.newRequest(ctx, "DELETE", fmt.Sprintf("/v1/domains/%s/aliases/%s", domain, alias))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AliasCredential is the IMAP/SMTP login of an alias. Like
+// NewCatchAllPassword, it masks the password when formatted.
+type AliasCredential struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func (a AliasCredential) String() string {
+	return fmt.Sprintf("{Username:%s Password:REDACTED}", a.Username)
+}
+
+func (a AliasCredential) GoString() string {
+	return a.String()
+}
+
+type AliasPasswordParameters struct {
+	NewPassword         *string
+	Password            *string
+	IsOverride          *bool
+	EmailedInstructions *string
+}
+
+func (c *Client) GenerateAliasPassword(domain string, alias string, parameters AliasPasswordParameters) (*AliasCredential, error) {
+	return c.GenerateAliasPasswordContext(context.Background(), domain, alias, parameters)
+}
+
+// GenerateAliasPasswordContext is like GenerateAliasPassword but carries ctx
+// with the request.
+func (c *Client) GenerateAliasPasswordContext(ctx context.Context, domain string, alias string, parameters AliasPasswordParameters) (*AliasCredential, error) {
+	req, err := c.newRequest(ctx, "POST", fmt.Sprintf("/v1/domains/%s/aliases/%s/generate-password", domain, alias))
 	if err != nil {
 		return nil, err
 	}
 
 	params := url.Values{}
-	params.Add("name", alias)
 
-	for k, v := range map[string]*bool{
-		"has_recipient_verification": parameters.HasRecipientVerification,
-		"is_enabled":                 parameters.IsEnabled,
+	for k, v := range map[string]*string{
+		"new_password":         parameters.NewPassword,
+		"password":             parameters.Password,
+		"emailed_instructions": parameters.EmailedInstructions,
 	} {
 		if v != nil {
-			params.Add(k, strconv.FormatBool(*v))
+			params.Add(k, *v)
 		}
 	}
 
-	for k, v := range map[string]*[]string{
-		"recipients[]": parameters.Recipients,
-		"labels[]":     parameters.Labels,
-	} {
-		if v != nil {
-			for _, vv := range *v {
-				params.Add(k, vv)
-			}
-		}
+	if parameters.IsOverride != nil {
+		params.Add("is_override", strconv.FormatBool(*parameters.IsOverride))
 	}
 
-	req.Body = io.NopCloser(strings.NewReader(params.Encode()))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	setFormBody(req, params)
 
 	res, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
 
-	var item Alias
+	var item AliasCredential
 
 	err = json.Unmarshal(res, &item)
 	if err != nil {
@@ -167,16 +321,97 @@ func (c *Client) UpdateAlias(domain string, alias string, parameters AliasParame
 	return &item, nil
 }
 
-func (c *Client) DeleteAlias(domain string, alias string) error {
-	req, err := c.newRequest("DELETE", fmt.Sprintf("/v1/domains/%s/aliases/%s", domain, alias))
-	if err != nil {
-		return err
+func (o ListAliasesOptions) encode() url.Values {
+	params := url.Values{}
+
+	encodePagination(params, o.Page, o.Limit)
+
+	for k, v := range map[string]string{
+		"name":      o.Name,
+		"recipient": o.Recipient,
+		"q":         o.Q,
+		"sort":      o.Sort,
+	} {
+		if v != "" {
+			params.Add(k, v)
+		}
 	}
 
-	_, err = c.doRequest(req)
-	if err != nil {
-		return err
+	if o.IsEnabled != nil {
+		params.Add("is_enabled", strconv.FormatBool(*o.IsEnabled))
 	}
 
-	return nil
+	for _, v := range o.Labels {
+		params.Add("labels[]", v)
+	}
+
+	return params
+}
+
+func (p AliasParameters) encode() url.Values {
+	params := url.Values{}
+
+	for k, v := range map[string]*string{
+		"description": p.Description,
+		"public_key":  p.PublicKey,
+		"max_quota":   p.MaxQuota,
+	} {
+		if v != nil {
+			params.Add(k, *v)
+		}
+	}
+
+	for k, v := range map[string]*bool{
+		"has_recipient_verification": p.HasRecipientVerification,
+		"is_enabled":                 p.IsEnabled,
+		"has_imap":                   p.HasImap,
+		"has_pgp":                    p.HasPgp,
+	} {
+		if v != nil {
+			params.Add(k, strconv.FormatBool(*v))
+		}
+	}
+
+	for k, v := range map[string]*int{
+		"error_code_if_disabled": p.ErrorCodeIfDisabled,
+		"retention":              p.Retention,
+	} {
+		if v != nil {
+			params.Add(k, strconv.Itoa(*v))
+		}
+	}
+
+	if p.Recipients != nil {
+		for _, v := range *p.Recipients {
+			params.Add("recipients[]", v)
+		}
+	}
+
+	encodeList(params, "labels", p.Labels)
+
+	if r := p.VacationResponder; r != nil {
+		if r.IsEnabled != nil {
+			params.Add("vacation_responder_is_enabled", strconv.FormatBool(*r.IsEnabled))
+		}
+
+		for k, v := range map[string]*time.Time{
+			"vacation_responder_start_date": r.StartDate,
+			"vacation_responder_end_date":   r.EndDate,
+		} {
+			if v != nil {
+				params.Add(k, v.UTC().Format(time.RFC3339))
+			}
+		}
+
+		for k, v := range map[string]*string{
+			"vacation_responder_subject": r.Subject,
+			"vacation_responder_message": r.Message,
+		} {
+			if v != nil {
+				params.Add(k, *v)
+			}
+		}
+	}
+
+	return params
 }
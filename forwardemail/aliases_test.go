@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -230,6 +232,125 @@ func TestClient_GetAliases(t *testing.T) {
 	}
 }
 
+func TestClient_ListAliases(t *testing.T) {
+	tests := []struct {
+		name      string
+		domain    string
+		options   ListAliasesOptions
+		headers   map[string]string
+		response  string
+		wantPath  string
+		wantQuery url.Values
+		want      *Page[Alias]
+	}{
+		{
+			name:     "last page",
+			domain:   "stark.com",
+			options:  ListAliasesOptions{Page: 4, Limit: 50},
+			headers:  map[string]string{"X-Page-Count": "4", "X-Item-Count": "151"},
+			response: `[{"name": "tony"}]`,
+			wantPath: "/v1/domains/stark.com/aliases",
+			wantQuery: url.Values{
+				"page":  {"4"},
+				"limit": {"50"},
+			},
+			want: &Page[Alias]{
+				Items:     []Alias{{Name: "tony"}},
+				Page:      4,
+				PageCount: 4,
+				ItemCount: 151,
+			},
+		},
+		{
+			name:   "filters",
+			domain: "stark.com",
+			options: ListAliasesOptions{
+				Name:      "tony",
+				Recipient: "bob@corp.com",
+				Q:         "armor",
+				IsEnabled: pointBool(false),
+				Labels:    []string{"suits", "lab"},
+				Sort:      "-created_at",
+			},
+			response: `[]`,
+			wantPath: "/v1/domains/stark.com/aliases",
+			wantQuery: url.Values{
+				"name":       {"tony"},
+				"recipient":  {"bob@corp.com"},
+				"q":          {"armor"},
+				"is_enabled": {"false"},
+				"labels[]":   {"suits", "lab"},
+				"sort":       {"-created_at"},
+			},
+			want: &Page[Alias]{
+				Items: []Alias{},
+				Page:  1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			var gotQuery url.Values
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath, gotQuery = r.URL.Path, r.URL.Query()
+				for k, v := range tt.headers {
+					w.Header().Set(k, v)
+				}
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got, _ := c.ListAliases(tt.domain, tt.options)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if gotPath != tt.wantPath {
+				t.Fatalf("unexpected path %s", gotPath)
+			}
+			if diff := cmp.Diff(tt.wantQuery, gotQuery); diff != "" {
+				t.Fatalf("queries are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_AllAliases(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "2" {
+			w.Header().Set("Link", `<https://api.forwardemail.net/v1/domains/stark.com/aliases?page=1>; rel="prev"`)
+			fmt.Fprintf(w, `[{"name": "pepper"}]`)
+			return
+		}
+		w.Header().Set("Link", `<https://api.forwardemail.net/v1/domains/stark.com/aliases?page=2>; rel="next"`)
+		fmt.Fprintf(w, `[{"name": "tony"}, {"name": "james"}]`)
+	}))
+	defer svr.Close()
+
+	c := NewClient(ClientOptions{
+		ApiUrl: svr.URL,
+	})
+
+	var got []string
+	for alias, err := range c.AllAliases("stark.com", ListAliasesOptions{}) {
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		got = append(got, alias.Name)
+	}
+
+	if diff := cmp.Diff([]string{"tony", "james", "pepper"}, got); diff != "" {
+		t.Fatalf("values are not the same %s", diff)
+	}
+}
+
 func TestClient_CreateAlias(t *testing.T) {
 	type request struct {
 		domain string
@@ -237,14 +358,19 @@ func TestClient_CreateAlias(t *testing.T) {
 		params AliasParameters
 	}
 
+	vacationStart := parseTime("2023-12-22T00:00:00Z")
+	vacationEnd := parseTime("2024-01-02T00:00:00Z")
+
 	tests := []struct {
-		name string
-		req  request
-		res  string
-		want *Alias
+		name     string
+		req      request
+		res      string
+		wantForm url.Values
+		want     *Alias
 	}{
 		{
-			name: "no data",
+			name:     "no data",
+			wantForm: url.Values{"name": {""}},
 		},
 		{
 			name: "ok",
@@ -282,6 +408,13 @@ func TestClient_CreateAlias(t *testing.T) {
 				"created_at": "2023-10-10T20:12:46.588Z",
 				"updated_at": "2023-11-11T22:12:42.533Z"
 			}`,
+			wantForm: url.Values{
+				"name":                       {"*"},
+				"recipients[]":               {"james@rhodes.com"},
+				"labels[]":                   {"catch-all"},
+				"is_enabled":                 {"true"},
+				"has_recipient_verification": {"true"},
+			},
 			want: &Alias{
 				Account: Account{
 					Email:       "tony@stark.com",
@@ -303,11 +436,132 @@ func TestClient_CreateAlias(t *testing.T) {
 				UpdatedAt:                parseTime("2023-11-11T22:12:42.533Z"),
 			},
 		},
+		{
+			name: "all settings",
+			req: request{
+				domain: "stark.com",
+				alias:  "pepper",
+				params: AliasParameters{
+					Description:         pointString("CEO mailbox"),
+					Recipients:          pointSliceOfStrings([]string{"pepper@potts.com", "happy@stark.com"}),
+					Labels:              pointSliceOfStrings([]string{}),
+					IsEnabled:           pointBool(false),
+					ErrorCodeIfDisabled: pointInt(AliasErrorCodeSoftReject),
+					HasImap:             pointBool(true),
+					HasPgp:              pointBool(true),
+					PublicKey:           pointString("-----BEGIN PGP PUBLIC KEY BLOCK-----\n...\n-----END PGP PUBLIC KEY BLOCK-----"),
+					MaxQuota:            pointString("1 GB"),
+					Retention:           pointInt(14),
+					VacationResponder: &AliasVacationResponderParameters{
+						IsEnabled: pointBool(true),
+						StartDate: &vacationStart,
+						EndDate:   &vacationEnd,
+						Subject:   pointString("Out of office"),
+						Message:   pointString("Back in January."),
+					},
+				},
+			},
+			res: `{
+				"user": {
+				  "email": "tony@stark.com",
+				  "display_name": "tony@stark.com",
+				  "id": "59ad551ae6fb4a4c53427ca38079f029"
+				},
+				"domain": {
+				  "name": "stark.com",
+				  "id": "15ff615b6180f1fc7faf40e6"
+				},
+				"name": "pepper",
+				"description": "CEO mailbox",
+				"labels": [],
+				"is_enabled": false,
+				"error_code_if_disabled": 421,
+				"has_recipient_verification": true,
+				"has_imap": true,
+				"has_pgp": true,
+				"public_key": "-----BEGIN PGP PUBLIC KEY BLOCK-----\n...\n-----END PGP PUBLIC KEY BLOCK-----",
+				"max_quota": 1073741824,
+				"storage_used": 52428800,
+				"retention": 14,
+				"vacation_responder": {
+				  "is_enabled": true,
+				  "start_date": "2023-12-22T00:00:00.000Z",
+				  "end_date": "2024-01-02T00:00:00.000Z",
+				  "subject": "Out of office",
+				  "message": "Back in January."
+				},
+				"recipients": ["pepper@potts.com", "happy@stark.com"],
+				"pending_recipients": ["happy@stark.com"],
+				"verified_recipients": ["pepper@potts.com"],
+				"id": "6525b03e0bde8f333ace5825",
+				"object": "alias",
+				"created_at": "2023-10-10T20:12:46.588Z",
+				"updated_at": "2023-11-11T22:12:42.533Z"
+			}`,
+			wantForm: url.Values{
+				"name":                          {"pepper"},
+				"description":                   {"CEO mailbox"},
+				"recipients[]":                  {"pepper@potts.com", "happy@stark.com"},
+				"labels":                        {""},
+				"is_enabled":                    {"false"},
+				"error_code_if_disabled":        {"421"},
+				"has_imap":                      {"true"},
+				"has_pgp":                       {"true"},
+				"public_key":                    {"-----BEGIN PGP PUBLIC KEY BLOCK-----\n...\n-----END PGP PUBLIC KEY BLOCK-----"},
+				"max_quota":                     {"1 GB"},
+				"retention":                     {"14"},
+				"vacation_responder_is_enabled": {"true"},
+				"vacation_responder_start_date": {"2023-12-22T00:00:00Z"},
+				"vacation_responder_end_date":   {"2024-01-02T00:00:00Z"},
+				"vacation_responder_subject":    {"Out of office"},
+				"vacation_responder_message":    {"Back in January."},
+			},
+			want: &Alias{
+				Account: Account{
+					Email:       "tony@stark.com",
+					DisplayName: "tony@stark.com",
+					Id:          "59ad551ae6fb4a4c53427ca38079f029",
+				},
+				Domain: Domain{
+					Name: "stark.com",
+					Id:   "15ff615b6180f1fc7faf40e6",
+				},
+				Name:                     "pepper",
+				Description:              "CEO mailbox",
+				Labels:                   []string{},
+				ErrorCodeIfDisabled:      AliasErrorCodeSoftReject,
+				HasRecipientVerification: true,
+				HasImap:                  true,
+				HasPgp:                   true,
+				PublicKey:                "-----BEGIN PGP PUBLIC KEY BLOCK-----\n...\n-----END PGP PUBLIC KEY BLOCK-----",
+				MaxQuota:                 1073741824,
+				StorageUsed:              52428800,
+				Retention:                14,
+				VacationResponder: AliasVacationResponder{
+					IsEnabled: true,
+					StartDate: parseTime("2023-12-22T00:00:00.000Z"),
+					EndDate:   parseTime("2024-01-02T00:00:00.000Z"),
+					Subject:   "Out of office",
+					Message:   "Back in January.",
+				},
+				Recipients:         []string{"pepper@potts.com", "happy@stark.com"},
+				PendingRecipients:  []string{"happy@stark.com"},
+				VerifiedRecipients: []string{"pepper@potts.com"},
+				Id:                 "6525b03e0bde8f333ace5825",
+				Object:             "alias",
+				CreatedAt:          parseTime("2023-10-10T20:12:46.588Z"),
+				UpdatedAt:          parseTime("2023-11-11T22:12:42.533Z"),
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			var gotForm url.Values
+
 			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				gotForm = r.PostForm
 				fmt.Fprintf(w, tt.res)
 			}))
 			defer svr.Close()
@@ -320,6 +574,9 @@ func TestClient_CreateAlias(t *testing.T) {
 			if diff := cmp.Diff(tt.want, got); diff != "" {
 				t.Fatalf("values are not the same %s", diff)
 			}
+			if diff := cmp.Diff(tt.wantForm, gotForm); diff != "" {
+				t.Fatalf("form values are not the same %s", diff)
+			}
 		})
 	}
 }
@@ -331,14 +588,19 @@ func TestClient_UpdateAlias(t *testing.T) {
 		params AliasParameters
 	}
 
+	vacationStart := parseTime("2023-12-22T00:00:00Z")
+	vacationEnd := parseTime("2024-01-02T00:00:00Z")
+
 	tests := []struct {
-		name string
-		req  request
-		res  string
-		want *Alias
+		name     string
+		req      request
+		res      string
+		wantForm url.Values
+		want     *Alias
 	}{
 		{
-			name: "no data",
+			name:     "no data",
+			wantForm: url.Values{"name": {""}},
 		},
 		{
 			name: "ok",
@@ -377,6 +639,13 @@ func TestClient_UpdateAlias(t *testing.T) {
 				"created_at": "2023-10-10T20:12:46.588Z",
 				"updated_at": "2023-11-11T22:12:42.533Z"
 			}`,
+			wantForm: url.Values{
+				"name":                       {"james"},
+				"recipients[]":               {"james@rhodes.com"},
+				"labels[]":                   {"catch-all", "friends"},
+				"is_enabled":                 {"true"},
+				"has_recipient_verification": {"true"},
+			},
 			want: &Alias{
 				Account: Account{
 					Email:       "tony@stark.com",
@@ -398,11 +667,161 @@ func TestClient_UpdateAlias(t *testing.T) {
 				UpdatedAt:                parseTime("2023-11-11T22:12:42.533Z"),
 			},
 		},
+		{
+			name: "all settings",
+			req: request{
+				domain: "stark.com",
+				alias:  "pepper",
+				params: AliasParameters{
+					Description:         pointString("CEO mailbox"),
+					Recipients:          pointSliceOfStrings([]string{"pepper@potts.com", "happy@stark.com"}),
+					Labels:              pointSliceOfStrings([]string{}),
+					IsEnabled:           pointBool(false),
+					ErrorCodeIfDisabled: pointInt(AliasErrorCodeSoftReject),
+					HasImap:             pointBool(true),
+					HasPgp:              pointBool(true),
+					PublicKey:           pointString("-----BEGIN PGP PUBLIC KEY BLOCK-----\n...\n-----END PGP PUBLIC KEY BLOCK-----"),
+					MaxQuota:            pointString("1 GB"),
+					Retention:           pointInt(14),
+					VacationResponder: &AliasVacationResponderParameters{
+						IsEnabled: pointBool(true),
+						StartDate: &vacationStart,
+						EndDate:   &vacationEnd,
+						Subject:   pointString("Out of office"),
+						Message:   pointString("Back in January."),
+					},
+				},
+			},
+			res: `{
+				"user": {
+				  "email": "tony@stark.com",
+				  "display_name": "tony@stark.com",
+				  "id": "59ad551ae6fb4a4c53427ca38079f029"
+				},
+				"domain": {
+				  "name": "stark.com",
+				  "id": "15ff615b6180f1fc7faf40e6"
+				},
+				"name": "pepper",
+				"description": "CEO mailbox",
+				"labels": [],
+				"is_enabled": false,
+				"error_code_if_disabled": 421,
+				"has_recipient_verification": true,
+				"has_imap": true,
+				"has_pgp": true,
+				"public_key": "-----BEGIN PGP PUBLIC KEY BLOCK-----\n...\n-----END PGP PUBLIC KEY BLOCK-----",
+				"max_quota": 1073741824,
+				"storage_used": 52428800,
+				"retention": 14,
+				"vacation_responder": {
+				  "is_enabled": true,
+				  "start_date": "2023-12-22T00:00:00.000Z",
+				  "end_date": "2024-01-02T00:00:00.000Z",
+				  "subject": "Out of office",
+				  "message": "Back in January."
+				},
+				"recipients": ["pepper@potts.com", "happy@stark.com"],
+				"pending_recipients": ["happy@stark.com"],
+				"verified_recipients": ["pepper@potts.com"],
+				"id": "6525b03e0bde8f333ace5825",
+				"object": "alias",
+				"created_at": "2023-10-10T20:12:46.588Z",
+				"updated_at": "2023-11-11T22:12:42.533Z"
+			}`,
+			wantForm: url.Values{
+				"name":                          {"pepper"},
+				"description":                   {"CEO mailbox"},
+				"recipients[]":                  {"pepper@potts.com", "happy@stark.com"},
+				"labels":                        {""},
+				"is_enabled":                    {"false"},
+				"error_code_if_disabled":        {"421"},
+				"has_imap":                      {"true"},
+				"has_pgp":                       {"true"},
+				"public_key":                    {"-----BEGIN PGP PUBLIC KEY BLOCK-----\n...\n-----END PGP PUBLIC KEY BLOCK-----"},
+				"max_quota":                     {"1 GB"},
+				"retention":                     {"14"},
+				"vacation_responder_is_enabled": {"true"},
+				"vacation_responder_start_date": {"2023-12-22T00:00:00Z"},
+				"vacation_responder_end_date":   {"2024-01-02T00:00:00Z"},
+				"vacation_responder_subject":    {"Out of office"},
+				"vacation_responder_message":    {"Back in January."},
+			},
+			want: &Alias{
+				Account: Account{
+					Email:       "tony@stark.com",
+					DisplayName: "tony@stark.com",
+					Id:          "59ad551ae6fb4a4c53427ca38079f029",
+				},
+				Domain: Domain{
+					Name: "stark.com",
+					Id:   "15ff615b6180f1fc7faf40e6",
+				},
+				Name:                     "pepper",
+				Description:              "CEO mailbox",
+				Labels:                   []string{},
+				ErrorCodeIfDisabled:      AliasErrorCodeSoftReject,
+				HasRecipientVerification: true,
+				HasImap:                  true,
+				HasPgp:                   true,
+				PublicKey:                "-----BEGIN PGP PUBLIC KEY BLOCK-----\n...\n-----END PGP PUBLIC KEY BLOCK-----",
+				MaxQuota:                 1073741824,
+				StorageUsed:              52428800,
+				Retention:                14,
+				VacationResponder: AliasVacationResponder{
+					IsEnabled: true,
+					StartDate: parseTime("2023-12-22T00:00:00.000Z"),
+					EndDate:   parseTime("2024-01-02T00:00:00.000Z"),
+					Subject:   "Out of office",
+					Message:   "Back in January.",
+				},
+				Recipients:         []string{"pepper@potts.com", "happy@stark.com"},
+				PendingRecipients:  []string{"happy@stark.com"},
+				VerifiedRecipients: []string{"pepper@potts.com"},
+				Id:                 "6525b03e0bde8f333ace5825",
+				Object:             "alias",
+				CreatedAt:          parseTime("2023-10-10T20:12:46.588Z"),
+				UpdatedAt:          parseTime("2023-11-11T22:12:42.533Z"),
+			},
+		},
+		{
+			name: "clear labels",
+			req: request{
+				domain: "stark.com",
+				alias:  "tony",
+				params: AliasParameters{
+					Recipients: pointSliceOfStrings([]string{}),
+					Labels:     pointSliceOfStrings([]string{}),
+				},
+			},
+			res: `{
+				"name": "tony",
+				"labels": [],
+				"recipients": ["tony@stark.com"],
+				"id": "6525b03e0bde8f333ace5825",
+				"object": "alias"
+			}`,
+			wantForm: url.Values{
+				"name":   {"tony"},
+				"labels": {""},
+			},
+			want: &Alias{
+				Name:       "tony",
+				Labels:     []string{},
+				Recipients: []string{"tony@stark.com"},
+				Id:         "6525b03e0bde8f333ace5825",
+				Object:     "alias",
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			var gotForm url.Values
+
 			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				gotForm = r.PostForm
 				fmt.Fprintf(w, tt.res)
 			}))
 			defer svr.Close()
@@ -415,6 +834,9 @@ func TestClient_UpdateAlias(t *testing.T) {
 			if diff := cmp.Diff(tt.want, got); diff != "" {
 				t.Fatalf("values are not the same %s", diff)
 			}
+			if diff := cmp.Diff(tt.wantForm, gotForm); diff != "" {
+				t.Fatalf("form values are not the same %s", diff)
+			}
 		})
 	}
 }
@@ -447,7 +869,7 @@ func TestClient_DeleteAlias(t *testing.T) {
 				code: http.StatusInternalServerError,
 				body: "oh no",
 			},
-			want: fmt.Errorf("status: 500, body: oh no"),
+			want: &APIError{StatusCode: 500, Body: []byte("oh no")},
 		},
 	}
 
@@ -471,6 +893,104 @@ func TestClient_DeleteAlias(t *testing.T) {
 	}
 }
 
+func TestClient_GenerateAliasPassword(t *testing.T) {
+	type request struct {
+		domain string
+		alias  string
+		params AliasPasswordParameters
+	}
+
+	tests := []struct {
+		name     string
+		req      request
+		res      string
+		wantPath string
+		wantForm url.Values
+		want     *AliasCredential
+	}{
+		{
+			name:     "no data",
+			wantPath: "/v1/domains//aliases//generate-password",
+			wantForm: url.Values{},
+		},
+		{
+			name: "generated",
+			req: request{
+				domain: "stark.com",
+				alias:  "tony",
+			},
+			res: `{
+				"username": "tony@stark.com",
+				"password": "Jarv1s-Gen3rated"
+			}`,
+			wantPath: "/v1/domains/stark.com/aliases/tony/generate-password",
+			wantForm: url.Values{},
+			want: &AliasCredential{
+				Username: "tony@stark.com",
+				Password: "Jarv1s-Gen3rated",
+			},
+		},
+		{
+			name: "custom password emailed",
+			req: request{
+				domain: "stark.com",
+				alias:  "tony",
+				params: AliasPasswordParameters{
+					NewPassword:         pointString("i-am-iron-man"),
+					IsOverride:          pointBool(true),
+					EmailedInstructions: pointString("pepper@stark.com"),
+				},
+			},
+			res: `{
+				"username": "tony@stark.com",
+				"password": "i-am-iron-man"
+			}`,
+			wantPath: "/v1/domains/stark.com/aliases/tony/generate-password",
+			wantForm: url.Values{
+				"new_password":         {"i-am-iron-man"},
+				"is_override":          {"true"},
+				"emailed_instructions": {"pepper@stark.com"},
+			},
+			want: &AliasCredential{
+				Username: "tony@stark.com",
+				Password: "i-am-iron-man",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			var gotForm url.Values
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				gotPath, gotForm = r.URL.Path, r.PostForm
+				fmt.Fprintf(w, tt.res)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got, _ := c.GenerateAliasPassword(tt.req.domain, tt.req.alias, tt.req.params)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if gotPath != tt.wantPath {
+				t.Fatalf("unexpected path %s", gotPath)
+			}
+			if diff := cmp.Diff(tt.wantForm, gotForm); diff != "" {
+				t.Fatalf("form values are not the same %s", diff)
+			}
+			if got != nil && strings.Contains(fmt.Sprintf("%+v", got), got.Password) {
+				t.Fatalf("password is leaked")
+			}
+		})
+	}
+}
+
 func pointSliceOfStrings(s []string) *[]string {
 	return &s
 }
@@ -0,0 +1,354 @@
+package forwardemail
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type Calendar struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Color       string    `json:"color"`
+	Timezone    string    `json:"timezone"`
+	Ctag        string    `json:"ctag"`
+	Id          string    `json:"id"`
+	Object      string    `json:"object"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CalendarParameters struct {
+	Name        *string
+	Description *string
+	Color       *string
+	Timezone    *string
+}
+
+// CalendarEvent is stored by the API as an iCalendar object in Ical. The
+// remaining fields mirror its VEVENT; see ParseICalendar and ICalendar.
+type CalendarEvent struct {
+	CalendarId  string      `json:"calendar_id"`
+	Ical        string      `json:"ical"`
+	Uid         string      `json:"uid"`
+	Summary     string      `json:"summary"`
+	Description string      `json:"description"`
+	Location    string      `json:"location"`
+	Start       time.Time   `json:"start_date"`
+	End         time.Time   `json:"end_date"`
+	AllDay      bool        `json:"is_all_day"`
+	Recurrence  string      `json:"rrule"`
+	ExDates     []time.Time `json:"exdates"`
+	// RecurrenceId marks the event as an override of the occurrence of a
+	// recurring event with the same Uid that starts at this time.
+	RecurrenceId time.Time `json:"recurrence_id"`
+	Id           string    `json:"id"`
+	Object       string    `json:"object"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type CalendarEventParameters struct {
+	CalendarId *string
+	Ical       *string
+}
+
+func (c *Client) GetCalendars() ([]Calendar, error) {
+	return c.GetCalendarsContext(context.Background())
+}
+
+// GetCalendarsContext is like GetCalendars but carries ctx with the request.
+func (c *Client) GetCalendarsContext(ctx context.Context) ([]Calendar, error) {
+	req, err := c.newAliasRequest(ctx, "GET", "/v1/calendars")
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []Calendar
+
+	err = json.Unmarshal(res, &items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (c *Client) GetCalendar(id string) (*Calendar, error) {
+	return c.GetCalendarContext(context.Background(), id)
+}
+
+// GetCalendarContext is like GetCalendar but carries ctx with the request.
+func (c *Client) GetCalendarContext(ctx context.Context, id string) (*Calendar, error) {
+	req, err := c.newAliasRequest(ctx, "GET", fmt.Sprintf("/v1/calendars/%s", id))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item Calendar
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) CreateCalendar(parameters CalendarParameters) (*Calendar, error) {
+	return c.CreateCalendarContext(context.Background(), parameters)
+}
+
+// CreateCalendarContext is like CreateCalendar but carries ctx with the
+// request.
+func (c *Client) CreateCalendarContext(ctx context.Context, parameters CalendarParameters) (*Calendar, error) {
+	req, err := c.newAliasRequest(ctx, "POST", "/v1/calendars")
+	if err != nil {
+		return nil, err
+	}
+
+	setFormBody(req, parameters.encode())
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item Calendar
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) UpdateCalendar(id string, parameters CalendarParameters) (*Calendar, error) {
+	return c.UpdateCalendarContext(context.Background(), id, parameters)
+}
+
+// UpdateCalendarContext is like UpdateCalendar but carries ctx with the
+// request.
+func (c *Client) UpdateCalendarContext(ctx context.Context, id string, parameters CalendarParameters) (*Calendar, error) {
+	req, err := c.newAliasRequest(ctx, "PUT", fmt.Sprintf("/v1/calendars/%s", id))
+	if err != nil {
+		return nil, err
+	}
+
+	setFormBody(req, parameters.encode())
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item Calendar
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) DeleteCalendar(id string) error {
+	return c.DeleteCalendarContext(context.Background(), id)
+}
+
+// DeleteCalendarContext is like DeleteCalendar but carries ctx with the
+// request.
+func (c *Client) DeleteCalendarContext(ctx context.Context, id string) error {
+	req, err := c.newAliasRequest(ctx, "DELETE", fmt.Sprintf("/v1/calendars/%s", id))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) GetCalendarEvents(calendar string) ([]CalendarEvent, error) {
+	return c.GetCalendarEventsContext(context.Background(), calendar)
+}
+
+// GetCalendarEventsContext is like GetCalendarEvents but carries ctx with the
+// request.
+func (c *Client) GetCalendarEventsContext(ctx context.Context, calendar string) ([]CalendarEvent, error) {
+	req, err := c.newAliasRequest(ctx, "GET", "/v1/calendar-events")
+	if err != nil {
+		return nil, err
+	}
+
+	if calendar != "" {
+		req.URL.RawQuery = url.Values{"calendar_id": {calendar}}.Encode()
+	}
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []CalendarEvent
+
+	err = json.Unmarshal(res, &items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (c *Client) GetCalendarEvent(id string) (*CalendarEvent, error) {
+	return c.GetCalendarEventContext(context.Background(), id)
+}
+
+// GetCalendarEventContext is like GetCalendarEvent but carries ctx with the
+// request.
+func (c *Client) GetCalendarEventContext(ctx context.Context, id string) (*CalendarEvent, error) {
+	req, err := c.newAliasRequest(ctx, "GET", fmt.Sprintf("/v1/calendar-events/%s", id))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item CalendarEvent
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) CreateCalendarEvent(parameters CalendarEventParameters) (*CalendarEvent, error) {
+	return c.CreateCalendarEventContext(context.Background(), parameters)
+}
+
+// CreateCalendarEventContext is like CreateCalendarEvent but carries ctx with
+// the request.
+func (c *Client) CreateCalendarEventContext(ctx context.Context, parameters CalendarEventParameters) (*CalendarEvent, error) {
+	req, err := c.newAliasRequest(ctx, "POST", "/v1/calendar-events")
+	if err != nil {
+		return nil, err
+	}
+
+	setFormBody(req, parameters.encode())
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item CalendarEvent
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) UpdateCalendarEvent(id string, parameters CalendarEventParameters) (*CalendarEvent, error) {
+	return c.UpdateCalendarEventContext(context.Background(), id, parameters)
+}
+
+// UpdateCalendarEventContext is like UpdateCalendarEvent but carries ctx with
+// the request.
+func (c *Client) UpdateCalendarEventContext(ctx context.Context, id string, parameters CalendarEventParameters) (*CalendarEvent, error) {
+	req, err := c.newAliasRequest(ctx, "PUT", fmt.Sprintf("/v1/calendar-events/%s", id))
+	if err != nil {
+		return nil, err
+	}
+
+	setFormBody(req, parameters.encode())
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item CalendarEvent
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) DeleteCalendarEvent(id string) error {
+	return c.DeleteCalendarEventContext(context.Background(), id)
+}
+
+// DeleteCalendarEventContext is like DeleteCalendarEvent but carries ctx with
+// the request.
+func (c *Client) DeleteCalendarEventContext(ctx context.Context, id string) error {
+	req, err := c.newAliasRequest(ctx, "DELETE", fmt.Sprintf("/v1/calendar-events/%s", id))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p CalendarParameters) encode() url.Values {
+	params := url.Values{}
+
+	for k, v := range map[string]*string{
+		"name":        p.Name,
+		"description": p.Description,
+		"color":       p.Color,
+		"timezone":    p.Timezone,
+	} {
+		if v != nil {
+			params.Add(k, *v)
+		}
+	}
+
+	return params
+}
+
+func (p CalendarEventParameters) encode() url.Values {
+	params := url.Values{}
+
+	for k, v := range map[string]*string{
+		"calendar_id": p.CalendarId,
+		"ical":        p.Ical,
+	} {
+		if v != nil {
+			params.Add(k, *v)
+		}
+	}
+
+	return params
+}
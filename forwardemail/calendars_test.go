@@ -0,0 +1,644 @@
+package forwardemail
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient_GetCalendars(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     []Calendar
+	}{
+		{
+			name: "no data",
+		},
+		{
+			name: "ok",
+			response: `[
+				{
+				  "name": "On-call",
+				  "description": "Stark Industries on-call rotation",
+				  "color": "#b22222",
+				  "timezone": "America/New_York",
+				  "ctag": "1697796766",
+				  "id": "6531b03e0bde8f333ace9001",
+				  "object": "calendar",
+				  "created_at": "2023-10-20T10:12:46.588Z",
+				  "updated_at": "2023-10-20T10:12:46.588Z"
+				}
+			]`,
+			want: []Calendar{
+				{
+					Name:        "On-call",
+					Description: "Stark Industries on-call rotation",
+					Color:       "#b22222",
+					Timezone:    "America/New_York",
+					Ctag:        "1697796766",
+					Id:          "6531b03e0bde8f333ace9001",
+					Object:      "calendar",
+					CreatedAt:   parseTime("2023-10-20T10:12:46.588Z"),
+					UpdatedAt:   parseTime("2023-10-20T10:12:46.588Z"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUser, gotPassword string
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotUser, gotPassword, _ = r.BasicAuth()
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				ApiKey:        "4e4d6c332b6fe62a63afe56171fd3725",
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			})
+
+			got, _ := c.GetCalendars()
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if gotUser != "tony@stark.com" || gotPassword != "i-am-iron-man" {
+				t.Fatalf("unexpected credentials %s:%s", gotUser, gotPassword)
+			}
+		})
+	}
+}
+
+func TestClient_GetCalendar(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		response string
+		want     *Calendar
+	}{
+		{
+			name: "no data",
+		},
+		{
+			name: "ok",
+			id:   "6531b03e0bde8f333ace9001",
+			response: `{
+				"name": "On-call",
+				"id": "6531b03e0bde8f333ace9001",
+				"object": "calendar"
+			}`,
+			want: &Calendar{
+				Name:   "On-call",
+				Id:     "6531b03e0bde8f333ace9001",
+				Object: "calendar",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			})
+
+			got, _ := c.GetCalendar(tt.id)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_CreateCalendar(t *testing.T) {
+	tests := []struct {
+		name       string
+		parameters CalendarParameters
+		response   string
+		wantForm   url.Values
+		want       *Calendar
+	}{
+		{
+			name:     "no data",
+			wantForm: url.Values{},
+		},
+		{
+			name: "ok",
+			parameters: CalendarParameters{
+				Name:     pointString("Launches"),
+				Color:    pointString("#ffd700"),
+				Timezone: pointString("Europe/Berlin"),
+			},
+			response: `{
+				"name": "Launches",
+				"color": "#ffd700",
+				"timezone": "Europe/Berlin",
+				"id": "6531b03e0bde8f333ace9002",
+				"object": "calendar"
+			}`,
+			wantForm: url.Values{
+				"name":     {"Launches"},
+				"color":    {"#ffd700"},
+				"timezone": {"Europe/Berlin"},
+			},
+			want: &Calendar{
+				Name:     "Launches",
+				Color:    "#ffd700",
+				Timezone: "Europe/Berlin",
+				Id:       "6531b03e0bde8f333ace9002",
+				Object:   "calendar",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotForm url.Values
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				gotForm = r.PostForm
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			})
+
+			got, _ := c.CreateCalendar(tt.parameters)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantForm, gotForm); diff != "" {
+				t.Fatalf("form values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_UpdateCalendar(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		parameters CalendarParameters
+		response   string
+		wantPath   string
+		wantForm   url.Values
+		want       *Calendar
+	}{
+		{
+			name:     "no data",
+			wantPath: "/v1/calendars/",
+			wantForm: url.Values{},
+		},
+		{
+			name: "ok",
+			id:   "6531b03e0bde8f333ace9002",
+			parameters: CalendarParameters{
+				Description: pointString("Rocket launches"),
+			},
+			response: `{
+				"name": "Launches",
+				"description": "Rocket launches",
+				"id": "6531b03e0bde8f333ace9002",
+				"object": "calendar"
+			}`,
+			wantPath: "/v1/calendars/6531b03e0bde8f333ace9002",
+			wantForm: url.Values{
+				"description": {"Rocket launches"},
+			},
+			want: &Calendar{
+				Name:        "Launches",
+				Description: "Rocket launches",
+				Id:          "6531b03e0bde8f333ace9002",
+				Object:      "calendar",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			var gotForm url.Values
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				gotPath, gotForm = r.URL.Path, r.PostForm
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			})
+
+			got, _ := c.UpdateCalendar(tt.id, tt.parameters)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if gotPath != tt.wantPath {
+				t.Fatalf("unexpected path %s", gotPath)
+			}
+			if diff := cmp.Diff(tt.wantForm, gotForm); diff != "" {
+				t.Fatalf("form values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteCalendar(t *testing.T) {
+	type response struct {
+		code int
+		body string
+	}
+
+	tests := []struct {
+		name string
+		id   string
+		resp response
+		want error
+	}{
+		{
+			name: "ok",
+			resp: response{
+				code: http.StatusNoContent,
+			},
+		},
+		{
+			name: "not ok",
+			resp: response{
+				code: http.StatusInternalServerError,
+				body: "oh no",
+			},
+			want: &APIError{StatusCode: 500, Body: []byte("oh no")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.resp.code)
+				fmt.Fprintf(w, tt.resp.body)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			})
+
+			got := c.DeleteCalendar(tt.id)
+			if diff := cmp.Diff(tt.want, got, cmp.Comparer(equateErrorMessage)); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_GetCalendarEvents(t *testing.T) {
+	tests := []struct {
+		name      string
+		calendar  string
+		response  string
+		wantQuery url.Values
+		want      []CalendarEvent
+	}{
+		{
+			name:      "no data",
+			wantQuery: url.Values{},
+		},
+		{
+			name:     "ok",
+			calendar: "6531b03e0bde8f333ace9001",
+			response: `[
+				{
+				  "calendar_id": "6531b03e0bde8f333ace9001",
+				  "ical": "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n",
+				  "uid": "on-call-1@stark.com",
+				  "summary": "On-call",
+				  "start_date": "2023-10-02T13:00:00.000Z",
+				  "end_date": "2023-10-02T21:00:00.000Z",
+				  "is_all_day": false,
+				  "rrule": "FREQ=WEEKLY;BYDAY=MO",
+				  "id": "6531b03e0bde8f333ace9101",
+				  "object": "calendar_event",
+				  "created_at": "2023-10-20T10:12:46.588Z",
+				  "updated_at": "2023-10-20T10:12:46.588Z"
+				}
+			]`,
+			wantQuery: url.Values{
+				"calendar_id": {"6531b03e0bde8f333ace9001"},
+			},
+			want: []CalendarEvent{
+				{
+					CalendarId: "6531b03e0bde8f333ace9001",
+					Ical:       "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n",
+					Uid:        "on-call-1@stark.com",
+					Summary:    "On-call",
+					Start:      parseTime("2023-10-02T13:00:00.000Z"),
+					End:        parseTime("2023-10-02T21:00:00.000Z"),
+					Recurrence: "FREQ=WEEKLY;BYDAY=MO",
+					Id:         "6531b03e0bde8f333ace9101",
+					Object:     "calendar_event",
+					CreatedAt:  parseTime("2023-10-20T10:12:46.588Z"),
+					UpdatedAt:  parseTime("2023-10-20T10:12:46.588Z"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotQuery url.Values
+			var gotUser, gotPassword string
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotQuery = r.URL.Query()
+				gotUser, gotPassword, _ = r.BasicAuth()
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				ApiKey:        "4e4d6c332b6fe62a63afe56171fd3725",
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			})
+
+			got, _ := c.GetCalendarEvents(tt.calendar)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantQuery, gotQuery); diff != "" {
+				t.Fatalf("queries are not the same %s", diff)
+			}
+			if gotUser != "tony@stark.com" || gotPassword != "i-am-iron-man" {
+				t.Fatalf("unexpected credentials %s:%s", gotUser, gotPassword)
+			}
+		})
+	}
+}
+
+func TestClient_GetCalendarEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		response string
+		want     *CalendarEvent
+	}{
+		{
+			name: "no data",
+		},
+		{
+			name: "ok",
+			id:   "6531b03e0bde8f333ace9102",
+			response: `{
+				"calendar_id": "6531b03e0bde8f333ace9001",
+				"uid": "holiday@stark.com",
+				"start_date": "2023-12-25T00:00:00.000Z",
+				"is_all_day": true,
+				"id": "6531b03e0bde8f333ace9102",
+				"object": "calendar_event"
+			}`,
+			want: &CalendarEvent{
+				CalendarId: "6531b03e0bde8f333ace9001",
+				Uid:        "holiday@stark.com",
+				Start:      parseTime("2023-12-25T00:00:00.000Z"),
+				AllDay:     true,
+				Id:         "6531b03e0bde8f333ace9102",
+				Object:     "calendar_event",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			})
+
+			got, _ := c.GetCalendarEvent(tt.id)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_CreateCalendarEvent(t *testing.T) {
+	ics := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\nUID:launch@stark.com\r\nDTSTART:20231020T101246Z\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	tests := []struct {
+		name       string
+		parameters CalendarEventParameters
+		response   string
+		wantForm   url.Values
+		want       *CalendarEvent
+	}{
+		{
+			name:     "no data",
+			wantForm: url.Values{},
+		},
+		{
+			name: "ok",
+			parameters: CalendarEventParameters{
+				CalendarId: pointString("6531b03e0bde8f333ace9002"),
+				Ical:       pointString(ics),
+			},
+			response: `{
+				"calendar_id": "6531b03e0bde8f333ace9002",
+				"uid": "launch@stark.com",
+				"start_date": "2023-10-20T10:12:46.000Z",
+				"id": "6531b03e0bde8f333ace9103",
+				"object": "calendar_event"
+			}`,
+			wantForm: url.Values{
+				"calendar_id": {"6531b03e0bde8f333ace9002"},
+				"ical":        {ics},
+			},
+			want: &CalendarEvent{
+				CalendarId: "6531b03e0bde8f333ace9002",
+				Uid:        "launch@stark.com",
+				Start:      parseTime("2023-10-20T10:12:46.000Z"),
+				Id:         "6531b03e0bde8f333ace9103",
+				Object:     "calendar_event",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotForm url.Values
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				gotForm = r.PostForm
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			})
+
+			got, _ := c.CreateCalendarEvent(tt.parameters)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantForm, gotForm); diff != "" {
+				t.Fatalf("form values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_UpdateCalendarEvent(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		parameters CalendarEventParameters
+		response   string
+		wantPath   string
+		wantForm   url.Values
+		want       *CalendarEvent
+	}{
+		{
+			name:     "no data",
+			wantPath: "/v1/calendar-events/",
+			wantForm: url.Values{},
+		},
+		{
+			name: "move",
+			id:   "6531b03e0bde8f333ace9103",
+			parameters: CalendarEventParameters{
+				CalendarId: pointString("6531b03e0bde8f333ace9001"),
+			},
+			response: `{
+				"calendar_id": "6531b03e0bde8f333ace9001",
+				"id": "6531b03e0bde8f333ace9103",
+				"object": "calendar_event"
+			}`,
+			wantPath: "/v1/calendar-events/6531b03e0bde8f333ace9103",
+			wantForm: url.Values{
+				"calendar_id": {"6531b03e0bde8f333ace9001"},
+			},
+			want: &CalendarEvent{
+				CalendarId: "6531b03e0bde8f333ace9001",
+				Id:         "6531b03e0bde8f333ace9103",
+				Object:     "calendar_event",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			var gotForm url.Values
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				gotPath, gotForm = r.URL.Path, r.PostForm
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			})
+
+			got, _ := c.UpdateCalendarEvent(tt.id, tt.parameters)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if gotPath != tt.wantPath {
+				t.Fatalf("unexpected path %s", gotPath)
+			}
+			if diff := cmp.Diff(tt.wantForm, gotForm); diff != "" {
+				t.Fatalf("form values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteCalendarEvent(t *testing.T) {
+	type response struct {
+		code int
+		body string
+	}
+
+	tests := []struct {
+		name string
+		id   string
+		resp response
+		want error
+	}{
+		{
+			name: "ok",
+			resp: response{
+				code: http.StatusNoContent,
+			},
+		},
+		{
+			name: "not ok",
+			resp: response{
+				code: http.StatusInternalServerError,
+				body: "oh no",
+			},
+			want: &APIError{StatusCode: 500, Body: []byte("oh no")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.resp.code)
+				fmt.Fprintf(w, tt.resp.body)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			})
+
+			got := c.DeleteCalendarEvent(tt.id)
+			if diff := cmp.Diff(tt.want, got, cmp.Comparer(equateErrorMessage)); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package forwardemail
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type CatchAllPassword struct {
+	Description string    `json:"description"`
+	Id          string    `json:"id"`
+	Object      string    `json:"object"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewCatchAllPassword is returned by CreateCatchAllPassword and is the only
+// place the password is ever exposed. It masks the secret when formatted.
+type NewCatchAllPassword struct {
+	CatchAllPassword
+	Password string `json:"password"`
+}
+
+func (p NewCatchAllPassword) String() string {
+	return fmt.Sprintf("{Id:%s Description:%s Password:REDACTED}", p.Id, p.Description)
+}
+
+func (p NewCatchAllPassword) GoString() string {
+	return p.String()
+}
+
+type CatchAllPasswordParameters struct {
+	NewPassword *string
+	Description *string
+}
+
+func (c *Client) GetCatchAllPasswords(domain string) ([]CatchAllPassword, error) {
+	return c.GetCatchAllPasswordsContext(context.Background(), domain)
+}
+
+// GetCatchAllPasswordsContext is like GetCatchAllPasswords but carries ctx with
+// the request.
+func (c *Client) GetCatchAllPasswordsContext(ctx context.Context, domain string) ([]CatchAllPassword, error) {
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("/v1/domains/%s/catch-all-passwords", domain))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []CatchAllPassword
+
+	err = json.Unmarshal(res, &items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (c *Client) CreateCatchAllPassword(domain string, parameters CatchAllPasswordParameters) (*NewCatchAllPassword, error) {
+	return c.CreateCatchAllPasswordContext(context.Background(), domain, parameters)
+}
+
+// CreateCatchAllPasswordContext is like CreateCatchAllPassword but carries ctx
+// with the request.
+func (c *Client) CreateCatchAllPasswordContext(ctx context.Context, domain string, parameters CatchAllPasswordParameters) (*NewCatchAllPassword, error) {
+	req, err := c.newRequest(ctx, "POST", fmt.Sprintf("/v1/domains/%s/catch-all-passwords", domain))
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+
+	for k, v := range map[string]*string{
+		"new_password": parameters.NewPassword,
+		"description":  parameters.Description,
+	} {
+		if v != nil {
+			params.Add(k, *v)
+		}
+	}
+
+	setFormBody(req, params)
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item NewCatchAllPassword
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) DeleteCatchAllPassword(domain string, id string) error {
+	return c.DeleteCatchAllPasswordContext(context.Background(), domain, id)
+}
+
+// DeleteCatchAllPasswordContext is like DeleteCatchAllPassword but carries ctx
+// with the request.
+func (c *Client) DeleteCatchAllPasswordContext(ctx context.Context, domain string, id string) error {
+	req, err := c.newRequest(ctx, "DELETE", fmt.Sprintf("/v1/domains/%s/catch-all-passwords/%s", domain, id))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package forwardemail
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient_GetCatchAllPasswords(t *testing.T) {
+	tests := []struct {
+		name     string
+		domain   string
+		response string
+		want     []CatchAllPassword
+	}{
+		{
+			name: "no data",
+		},
+		{
+			name:   "ok",
+			domain: "stark.com",
+			response: `[
+				{
+				  "description": "support mailbox",
+				  "id": "6525b03e0bde8f333ace5901",
+				  "object": "catch-all-password",
+				  "created_at": "2023-10-10T20:12:46.588Z"
+				}
+			]`,
+			want: []CatchAllPassword{
+				{
+					Description: "support mailbox",
+					Id:          "6525b03e0bde8f333ace5901",
+					Object:      "catch-all-password",
+					CreatedAt:   parseTime("2023-10-10T20:12:46.588Z"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got, _ := c.GetCatchAllPasswords(tt.domain)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_CreateCatchAllPassword(t *testing.T) {
+	tests := []struct {
+		name       string
+		domain     string
+		parameters CatchAllPasswordParameters
+		response   string
+		wantForm   url.Values
+		want       *NewCatchAllPassword
+	}{
+		{
+			name:     "no data",
+			wantForm: url.Values{},
+		},
+		{
+			name:   "ok",
+			domain: "stark.com",
+			parameters: CatchAllPasswordParameters{
+				Description: pointString("support mailbox"),
+			},
+			response: `{
+				"description": "support mailbox",
+				"password": "c0ffee-s3cr3t",
+				"id": "6525b03e0bde8f333ace5901",
+				"object": "catch-all-password",
+				"created_at": "2023-10-10T20:12:46.588Z"
+			}`,
+			wantForm: url.Values{
+				"description": {"support mailbox"},
+			},
+			want: &NewCatchAllPassword{
+				CatchAllPassword: CatchAllPassword{
+					Description: "support mailbox",
+					Id:          "6525b03e0bde8f333ace5901",
+					Object:      "catch-all-password",
+					CreatedAt:   parseTime("2023-10-10T20:12:46.588Z"),
+				},
+				Password: "c0ffee-s3cr3t",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotForm url.Values
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				gotForm = r.PostForm
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got, _ := c.CreateCatchAllPassword(tt.domain, tt.parameters)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantForm, gotForm); diff != "" {
+				t.Fatalf("form values are not the same %s", diff)
+			}
+			if got != nil {
+				for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+					if s := fmt.Sprintf(format, got); strings.Contains(s, got.Password) {
+						t.Fatalf("password is leaked with %s: %s", format, s)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestClient_DeleteCatchAllPassword(t *testing.T) {
+	type response struct {
+		code int
+		body string
+	}
+
+	tests := []struct {
+		name   string
+		domain string
+		id     string
+		resp   response
+		want   error
+	}{
+		{
+			name: "ok",
+			resp: response{
+				code: http.StatusNoContent,
+			},
+		},
+		{
+			name: "not ok",
+			resp: response{
+				code: http.StatusInternalServerError,
+				body: "oh no",
+			},
+			want: &APIError{StatusCode: 500, Body: []byte("oh no")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.resp.code)
+				fmt.Fprintf(w, tt.resp.body)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got := c.DeleteCatchAllPassword(tt.domain, tt.id)
+			if diff := cmp.Diff(tt.want, got, cmp.Comparer(equateErrorMessage)); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package forwardemail
 
 import (
-	"fmt"
+	"context"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 )
 
 const (
@@ -13,55 +16,192 @@ const (
 type ClientOptions struct {
 	ApiKey string
 	ApiUrl string
+
+	// AliasUsername and AliasPassword authenticate mailbox endpoints such as
+	// messages, which are accessed as an alias rather than the account.
+	// Those endpoints return ErrNoAliasCredentials when either is empty.
+	AliasUsername string
+	AliasPassword string
+
+	// RetryPolicy retries transient failures. Nil disables retries.
+	RetryPolicy *RetryPolicy
+	// Limiter throttles every request, retries included. Nil disables it.
+	Limiter Limiter
 }
 
 type Client struct {
 	ApiKey string
 	ApiUrl string
 
+	AliasUsername string
+	AliasPassword string
+
+	RetryPolicy *RetryPolicy
+	Limiter     Limiter
+
 	HttpClient *http.Client
+	UserAgent  string
+	// Header is added to every request.
+	Header http.Header
+
+	rateLimitMu sync.Mutex
+	rateLimit   *RateLimit
 }
 
 // NewClient returns a new Forward Email API Client.
-func NewClient(options ClientOptions) *Client {
+func NewClient(options ClientOptions, opts ...Option) *Client {
 	apiUrl := forwardemailApiUrl
 	if options.ApiUrl != "" {
 		apiUrl = options.ApiUrl
 	}
 
-	return &Client{
-		ApiKey:     options.ApiKey,
-		ApiUrl:     apiUrl,
-		HttpClient: http.DefaultClient,
+	c := &Client{
+		ApiKey:        options.ApiKey,
+		ApiUrl:        apiUrl,
+		AliasUsername: options.AliasUsername,
+		AliasPassword: options.AliasPassword,
+		RetryPolicy:   options.RetryPolicy,
+		Limiter:       options.Limiter,
+		HttpClient:    http.DefaultClient,
+		UserAgent:     defaultUserAgent(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
+
+	return c
 }
 
-func (c *Client) newRequest(method, path string) (*http.Request, error) {
-	req, err := http.NewRequest(method, c.ApiUrl+path, nil)
+func (c *Client) newRequest(ctx context.Context, method, path string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.ApiUrl+path, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	for k, v := range c.Header {
+		req.Header[k] = append(req.Header[k], v...)
+	}
+
+	if c.UserAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
 	req.SetBasicAuth(c.ApiKey, "")
 
 	return req, nil
 }
 
+func (c *Client) newAliasRequest(ctx context.Context, method, path string) (*http.Request, error) {
+	if c.AliasUsername == "" || c.AliasPassword == "" {
+		return nil, ErrNoAliasCredentials
+	}
+
+	req, err := c.newRequest(ctx, method, path)
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(c.AliasUsername, c.AliasPassword)
+
+	return req, nil
+}
+
+// setFormBody encodes the params as the request body. GetBody is set so the
+// body can be rebuilt when the request is retried.
+func setFormBody(req *http.Request, params url.Values) {
+	body := params.Encode()
+
+	req.Body = io.NopCloser(strings.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(body)), nil
+	}
+	req.ContentLength = int64(len(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+}
+
+// encodeList adds the values as key[] fields. A non-nil empty list is sent
+// as an empty key field, so that updates can clear it.
+func encodeList(params url.Values, key string, values *[]string) {
+	if values == nil {
+		return
+	}
+
+	if len(*values) == 0 {
+		params.Add(key, "")
+		return
+	}
+
+	for _, v := range *values {
+		params.Add(key+"[]", v)
+	}
+}
+
 func (c *Client) doRequest(req *http.Request) ([]byte, error) {
-	res, err := c.HttpClient.Do(req)
+	res, err := c.doStreamRequest(req)
 	if err != nil {
 		return nil, err
 	}
 
 	defer res.Body.Close()
-	body, err := io.ReadAll(res.Body)
+
+	return io.ReadAll(res.Body)
+}
+
+// doStreamRequest returns a successful response with its body left open, so
+// large payloads can be consumed without buffering. The caller must close it.
+func (c *Client) doStreamRequest(req *http.Request) (*http.Response, error) {
+	res, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 
 	if res.StatusCode == http.StatusOK || res.StatusCode == http.StatusNoContent {
-		return body, err
+		return res, nil
+	}
+
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, fmt.Errorf("status: %d, body: %s", res.StatusCode, body)
+	return nil, newAPIError(req, res, body)
+}
+
+// do sends the request through the limiter, retrying transient failures as allowed by the retry
+// policy. The last response or error is returned once attempts run out.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	for retry := 1; ; retry++ {
+		if c.Limiter != nil {
+			if err := c.Limiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := c.HttpClient.Do(req)
+		if res != nil {
+			c.recordRateLimit(res.Header)
+		}
+
+		p := c.RetryPolicy
+		if p == nil || retry >= p.MaxAttempts || !p.shouldRetry(req, res, err) {
+			return res, err
+		}
+
+		delay := p.backoff(retry, res)
+
+		if res != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+			res.Body.Close()
+		}
+
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+
+		if err := rewind(req); err != nil {
+			return nil, err
+		}
+	}
 }
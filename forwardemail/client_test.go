@@ -1,10 +1,16 @@
 package forwardemail
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 func TestNewClient(t *testing.T) {
@@ -19,6 +25,7 @@ func TestNewClient(t *testing.T) {
 			want: &Client{
 				ApiUrl:     "https://api.forwardemail.net",
 				HttpClient: &http.Client{},
+				UserAgent:  "go-forwardemail/" + Version,
 			},
 		},
 		{
@@ -30,6 +37,7 @@ func TestNewClient(t *testing.T) {
 				ApiKey:     "4e4d6c332b6fe62a63afe56171fd3725",
 				ApiUrl:     "https://api.forwardemail.net",
 				HttpClient: &http.Client{},
+				UserAgent:  "go-forwardemail/" + Version,
 			},
 		},
 		{
@@ -40,18 +48,38 @@ func TestNewClient(t *testing.T) {
 			want: &Client{
 				ApiUrl:     "https://google.com",
 				HttpClient: &http.Client{},
+				UserAgent:  "go-forwardemail/" + Version,
+			},
+		},
+		{
+			name: "with alias credentials",
+			options: ClientOptions{
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			},
+			want: &Client{
+				ApiUrl:        "https://api.forwardemail.net",
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+				HttpClient:    &http.Client{},
+				UserAgent:     "go-forwardemail/" + Version,
 			},
 		},
 		{
 			name: "with everything at once",
 			options: ClientOptions{
-				ApiKey: "4e4d6c332b6fe62a63afe56171fd3725",
-				ApiUrl: "https://google.com",
+				ApiKey:        "4e4d6c332b6fe62a63afe56171fd3725",
+				ApiUrl:        "https://google.com",
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
 			},
 			want: &Client{
-				ApiKey:     "4e4d6c332b6fe62a63afe56171fd3725",
-				ApiUrl:     "https://google.com",
-				HttpClient: &http.Client{},
+				ApiKey:        "4e4d6c332b6fe62a63afe56171fd3725",
+				ApiUrl:        "https://google.com",
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+				HttpClient:    &http.Client{},
+				UserAgent:     "go-forwardemail/" + Version,
 			},
 		},
 	}
@@ -59,9 +87,181 @@ func TestNewClient(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got := NewClient(tt.options)
-			if diff := cmp.Diff(tt.want, got); diff != "" {
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreUnexported(Client{})); diff != "" {
 				t.Fatalf("values are not the same %s", diff)
 			}
 		})
 	}
 }
+
+func TestClient_Context(t *testing.T) {
+	calls := []struct {
+		name string
+		call func(ctx context.Context, c *Client) error
+	}{
+		{
+			name: "get domain",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetDomainContext(ctx, "stark.com")
+				return err
+			},
+		},
+		{
+			name: "create alias",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.CreateAliasContext(ctx, "stark.com", "tony", AliasParameters{})
+				return err
+			},
+		},
+		{
+			name: "get messages",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetMessagesContext(ctx, MessageSearchParameters{})
+				return err
+			},
+		},
+		{
+			name: "all domains",
+			call: func(ctx context.Context, c *Client) error {
+				for _, err := range c.AllDomainsContext(ctx, ListDomainsOptions{}) {
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+	}
+
+	contexts := []struct {
+		name    string
+		ctx     func() (context.Context, context.CancelFunc)
+		wantErr error
+	}{
+		{
+			name: "cancelled",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(20*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			wantErr: context.Canceled,
+		},
+		{
+			name: "deadline",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 20*time.Millisecond)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tc := range contexts {
+		for _, tt := range calls {
+			t.Run(tc.name+" "+tt.name, func(t *testing.T) {
+				release := make(chan struct{})
+
+				svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					<-release
+				}))
+				defer svr.Close()
+				defer close(release)
+
+				c := NewClient(ClientOptions{
+					ApiUrl:        svr.URL,
+					AliasUsername: "tony@stark.com",
+					AliasPassword: "i-am-iron-man",
+				})
+
+				ctx, cancel := tc.ctx()
+				defer cancel()
+
+				start := time.Now()
+				err := tt.call(ctx, c)
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("unexpected error %v", err)
+				}
+				if elapsed := time.Since(start); elapsed > time.Second {
+					t.Fatalf("call returned after %s", elapsed)
+				}
+			})
+		}
+	}
+}
+
+func TestClient_NoAliasCredentials(t *testing.T) {
+	tests := []struct {
+		name    string
+		options ClientOptions
+	}{
+		{
+			name:    "no credentials",
+			options: ClientOptions{ApiKey: "4e4d6c332b6fe62a63afe56171fd3725"},
+		},
+		{
+			name:    "no password",
+			options: ClientOptions{AliasUsername: "tony@stark.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				fmt.Fprint(w, `[]`)
+			}))
+			defer svr.Close()
+
+			tt.options.ApiUrl = svr.URL
+			c := NewClient(tt.options)
+
+			_, err := c.GetMessages(MessageSearchParameters{})
+			if !errors.Is(err, ErrNoAliasCredentials) {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if calls != 0 {
+				t.Fatalf("unexpected requests %d", calls)
+			}
+		})
+	}
+}
+
+func TestClient_DownloadLogsContext(t *testing.T) {
+	release := make(chan struct{})
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"id": "1", "message": "delivered"}`)
+		w.(http.Flusher).Flush()
+		<-release
+	}))
+	defer svr.Close()
+	defer close(release)
+
+	c := NewClient(ClientOptions{
+		ApiUrl: svr.URL,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	it, err := c.DownloadLogsContext(ctx, LogsParameters{})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer it.Close()
+
+	if !it.Next() || it.Entry().Id != "1" {
+		t.Fatalf("expected the first entry, got error %v", it.Err())
+	}
+
+	cancel()
+
+	if it.Next() {
+		t.Fatalf("expected the stream to stop")
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Fatalf("unexpected error %v", it.Err())
+	}
+}
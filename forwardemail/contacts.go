@@ -0,0 +1,210 @@
+package forwardemail
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type ContactValue struct {
+	Value string `json:"value"`
+	Type  string `json:"type"`
+}
+
+type Contact struct {
+	FullName     string         `json:"full_name"`
+	Emails       []ContactValue `json:"emails"`
+	PhoneNumbers []ContactValue `json:"phone_numbers"`
+	Content      string         `json:"content"`
+	Etag         string         `json:"etag"`
+	IsGroup      bool           `json:"is_group"`
+	Id           string         `json:"id"`
+	Object       string         `json:"object"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+// ContactParameters describes a contact either by its fields or by a raw
+// vCard in Content, which takes precedence on the server.
+type ContactParameters struct {
+	FullName     *string
+	Emails       *[]ContactValue
+	PhoneNumbers *[]ContactValue
+	Content      *string
+}
+
+// Parameters returns the parameters to recreate the contact, for example
+// after parsing it with ParseVCards.
+func (c Contact) Parameters() ContactParameters {
+	parameters := ContactParameters{
+		FullName:     &c.FullName,
+		Emails:       &c.Emails,
+		PhoneNumbers: &c.PhoneNumbers,
+	}
+
+	if c.Content != "" {
+		parameters.Content = &c.Content
+	}
+
+	return parameters
+}
+
+func (c *Client) GetContacts() ([]Contact, error) {
+	return c.GetContactsContext(context.Background())
+}
+
+// GetContactsContext is like GetContacts but carries ctx with the request.
+func (c *Client) GetContactsContext(ctx context.Context) ([]Contact, error) {
+	req, err := c.newAliasRequest(ctx, "GET", "/v1/contacts")
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []Contact
+
+	err = json.Unmarshal(res, &items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (c *Client) GetContact(id string) (*Contact, error) {
+	return c.GetContactContext(context.Background(), id)
+}
+
+// GetContactContext is like GetContact but carries ctx with the request.
+func (c *Client) GetContactContext(ctx context.Context, id string) (*Contact, error) {
+	req, err := c.newAliasRequest(ctx, "GET", fmt.Sprintf("/v1/contacts/%s", id))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item Contact
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) CreateContact(parameters ContactParameters) (*Contact, error) {
+	return c.CreateContactContext(context.Background(), parameters)
+}
+
+// CreateContactContext is like CreateContact but carries ctx with the request.
+func (c *Client) CreateContactContext(ctx context.Context, parameters ContactParameters) (*Contact, error) {
+	req, err := c.newAliasRequest(ctx, "POST", "/v1/contacts")
+	if err != nil {
+		return nil, err
+	}
+
+	setFormBody(req, parameters.encode())
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item Contact
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) UpdateContact(id string, parameters ContactParameters) (*Contact, error) {
+	return c.UpdateContactContext(context.Background(), id, parameters)
+}
+
+// UpdateContactContext is like UpdateContact but carries ctx with the request.
+func (c *Client) UpdateContactContext(ctx context.Context, id string, parameters ContactParameters) (*Contact, error) {
+	req, err := c.newAliasRequest(ctx, "PUT", fmt.Sprintf("/v1/contacts/%s", id))
+	if err != nil {
+		return nil, err
+	}
+
+	setFormBody(req, parameters.encode())
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item Contact
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) DeleteContact(id string) error {
+	return c.DeleteContactContext(context.Background(), id)
+}
+
+// DeleteContactContext is like DeleteContact but carries ctx with the request.
+func (c *Client) DeleteContactContext(ctx context.Context, id string) error {
+	req, err := c.newAliasRequest(ctx, "DELETE", fmt.Sprintf("/v1/contacts/%s", id))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p ContactParameters) encode() url.Values {
+	params := url.Values{}
+
+	for k, v := range map[string]*string{
+		"full_name": p.FullName,
+		"content":   p.Content,
+	} {
+		if v != nil {
+			params.Add(k, *v)
+		}
+	}
+
+	for k, v := range map[string]*[]ContactValue{
+		"emails":        p.Emails,
+		"phone_numbers": p.PhoneNumbers,
+	} {
+		if v != nil {
+			for i, vv := range *v {
+				prefix := k + "[" + strconv.Itoa(i) + "]"
+				params.Add(prefix+"[value]", vv.Value)
+				if vv.Type != "" {
+					params.Add(prefix+"[type]", vv.Type)
+				}
+			}
+		}
+	}
+
+	return params
+}
@@ -0,0 +1,341 @@
+package forwardemail
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient_GetContacts(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     []Contact
+	}{
+		{
+			name: "no data",
+		},
+		{
+			name: "ok",
+			response: `[
+				{
+				  "full_name": "James Rhodes",
+				  "emails": [{"value": "james@rhodes.com", "type": "work"}],
+				  "phone_numbers": [{"value": "+15555550100", "type": "cell"}],
+				  "content": "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:James Rhodes\r\nEND:VCARD\r\n",
+				  "etag": "\"1697796766\"",
+				  "is_group": false,
+				  "id": "6531b03e0bde8f333ace8001",
+				  "object": "contact",
+				  "created_at": "2023-10-20T10:12:46.588Z",
+				  "updated_at": "2023-10-20T10:12:46.588Z"
+				}
+			]`,
+			want: []Contact{
+				{
+					FullName:     "James Rhodes",
+					Emails:       []ContactValue{{Value: "james@rhodes.com", Type: "work"}},
+					PhoneNumbers: []ContactValue{{Value: "+15555550100", Type: "cell"}},
+					Content:      "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:James Rhodes\r\nEND:VCARD\r\n",
+					Etag:         `"1697796766"`,
+					Id:           "6531b03e0bde8f333ace8001",
+					Object:       "contact",
+					CreatedAt:    parseTime("2023-10-20T10:12:46.588Z"),
+					UpdatedAt:    parseTime("2023-10-20T10:12:46.588Z"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUser, gotPassword string
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotUser, gotPassword, _ = r.BasicAuth()
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "support@stark.com",
+				AliasPassword: "jarvis",
+			})
+
+			got, _ := c.GetContacts()
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if gotUser != "support@stark.com" || gotPassword != "jarvis" {
+				t.Fatalf("unexpected credentials %s:%s", gotUser, gotPassword)
+			}
+		})
+	}
+}
+
+func TestClient_GetContact(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		response string
+		want     *Contact
+	}{
+		{
+			name: "no data",
+		},
+		{
+			name: "ok",
+			id:   "6531b03e0bde8f333ace8002",
+			response: `{
+				"full_name": "Avengers",
+				"is_group": true,
+				"id": "6531b03e0bde8f333ace8002",
+				"object": "contact"
+			}`,
+			want: &Contact{
+				FullName: "Avengers",
+				IsGroup:  true,
+				Id:       "6531b03e0bde8f333ace8002",
+				Object:   "contact",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "support@stark.com",
+				AliasPassword: "jarvis",
+			})
+
+			got, _ := c.GetContact(tt.id)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_CreateContact(t *testing.T) {
+	tests := []struct {
+		name       string
+		parameters ContactParameters
+		response   string
+		wantForm   url.Values
+		want       *Contact
+	}{
+		{
+			name:     "no data",
+			wantForm: url.Values{},
+		},
+		{
+			name: "fields",
+			parameters: ContactParameters{
+				FullName:     pointString("Pepper Potts"),
+				Emails:       &[]ContactValue{{Value: "pepper@stark.com", Type: "work"}, {Value: "pepper@potts.com"}},
+				PhoneNumbers: &[]ContactValue{{Value: "+15555550101", Type: "cell"}},
+			},
+			response: `{
+				"full_name": "Pepper Potts",
+				"emails": [{"value": "pepper@stark.com", "type": "work"}, {"value": "pepper@potts.com"}],
+				"phone_numbers": [{"value": "+15555550101", "type": "cell"}],
+				"id": "6531b03e0bde8f333ace8003",
+				"object": "contact"
+			}`,
+			wantForm: url.Values{
+				"full_name":               {"Pepper Potts"},
+				"emails[0][value]":        {"pepper@stark.com"},
+				"emails[0][type]":         {"work"},
+				"emails[1][value]":        {"pepper@potts.com"},
+				"phone_numbers[0][value]": {"+15555550101"},
+				"phone_numbers[0][type]":  {"cell"},
+			},
+			want: &Contact{
+				FullName:     "Pepper Potts",
+				Emails:       []ContactValue{{Value: "pepper@stark.com", Type: "work"}, {Value: "pepper@potts.com"}},
+				PhoneNumbers: []ContactValue{{Value: "+15555550101", Type: "cell"}},
+				Id:           "6531b03e0bde8f333ace8003",
+				Object:       "contact",
+			},
+		},
+		{
+			name: "vcard",
+			parameters: ContactParameters{
+				Content: pointString("BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Happy Hogan\r\nEND:VCARD\r\n"),
+			},
+			response: `{
+				"full_name": "Happy Hogan",
+				"content": "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Happy Hogan\r\nEND:VCARD\r\n",
+				"id": "6531b03e0bde8f333ace8004",
+				"object": "contact"
+			}`,
+			wantForm: url.Values{
+				"content": {"BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Happy Hogan\r\nEND:VCARD\r\n"},
+			},
+			want: &Contact{
+				FullName: "Happy Hogan",
+				Content:  "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Happy Hogan\r\nEND:VCARD\r\n",
+				Id:       "6531b03e0bde8f333ace8004",
+				Object:   "contact",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotForm url.Values
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				gotForm = r.PostForm
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "support@stark.com",
+				AliasPassword: "jarvis",
+			})
+
+			got, _ := c.CreateContact(tt.parameters)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantForm, gotForm); diff != "" {
+				t.Fatalf("form values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_UpdateContact(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		parameters ContactParameters
+		response   string
+		wantPath   string
+		wantForm   url.Values
+		want       *Contact
+	}{
+		{
+			name:     "no data",
+			wantPath: "/v1/contacts/",
+			wantForm: url.Values{},
+		},
+		{
+			name: "ok",
+			id:   "6531b03e0bde8f333ace8003",
+			parameters: ContactParameters{
+				FullName: pointString("Virginia Potts"),
+			},
+			response: `{
+				"full_name": "Virginia Potts",
+				"id": "6531b03e0bde8f333ace8003",
+				"object": "contact"
+			}`,
+			wantPath: "/v1/contacts/6531b03e0bde8f333ace8003",
+			wantForm: url.Values{
+				"full_name": {"Virginia Potts"},
+			},
+			want: &Contact{
+				FullName: "Virginia Potts",
+				Id:       "6531b03e0bde8f333ace8003",
+				Object:   "contact",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			var gotForm url.Values
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				gotPath, gotForm = r.URL.Path, r.PostForm
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "support@stark.com",
+				AliasPassword: "jarvis",
+			})
+
+			got, _ := c.UpdateContact(tt.id, tt.parameters)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if gotPath != tt.wantPath {
+				t.Fatalf("unexpected path %s", gotPath)
+			}
+			if diff := cmp.Diff(tt.wantForm, gotForm); diff != "" {
+				t.Fatalf("form values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteContact(t *testing.T) {
+	type response struct {
+		code int
+		body string
+	}
+
+	tests := []struct {
+		name string
+		id   string
+		resp response
+		want error
+	}{
+		{
+			name: "ok",
+			resp: response{
+				code: http.StatusNoContent,
+			},
+		},
+		{
+			name: "not ok",
+			resp: response{
+				code: http.StatusInternalServerError,
+				body: "oh no",
+			},
+			want: &APIError{StatusCode: 500, Body: []byte("oh no")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.resp.code)
+				fmt.Fprintf(w, tt.resp.body)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "support@stark.com",
+				AliasPassword: "jarvis",
+			})
+
+			got := c.DeleteContact(tt.id)
+			if diff := cmp.Diff(tt.want, got, cmp.Comparer(equateErrorMessage)); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package forwardemail
+
+import (
+	"errors"
+	"html"
+	"net/mail"
+	"net/url"
+	"strings"
+)
+
+// Placeholders replaced by Forward Email when it sends a recipient
+// verification email from a custom template.
+const (
+	CustomVerificationLink = "VERIFICATION_LINK"
+	CustomVerificationFrom = "FROM_EMAIL"
+	CustomVerificationTo   = "TO_EMAIL"
+)
+
+// CustomVerification is the template of the email sent to recipients that
+// have to confirm forwarding when recipient verification is enabled.
+type CustomVerification struct {
+	Name        string `json:"name"`
+	From        string `json:"from"`
+	Subject     string `json:"subject"`
+	Html        string `json:"html"`
+	Text        string `json:"text"`
+	RedirectUrl string `json:"redirect"`
+}
+
+// CustomVerificationPreview is a template rendered with sample values.
+type CustomVerificationPreview struct {
+	From    string
+	Subject string
+	Html    string
+	Text    string
+}
+
+var customVerificationSamples = map[string]string{
+	CustomVerificationLink: "https://forwardemail.net/v/sample-verification-token",
+	CustomVerificationFrom: "sender@example.com",
+	CustomVerificationTo:   "recipient@example.com",
+}
+
+// Preview renders the template locally with sample placeholder values. It
+// fails when neither body contains the verification link, since recipients
+// would have no way to confirm.
+func (v CustomVerification) Preview() (*CustomVerificationPreview, error) {
+	if !strings.Contains(v.Html, CustomVerificationLink) && !strings.Contains(v.Text, CustomVerificationLink) {
+		return nil, errors.New("custom verification: template has no " + CustomVerificationLink + " placeholder")
+	}
+
+	var text, escaped []string
+	for k, v := range customVerificationSamples {
+		text = append(text, k, v)
+		escaped = append(escaped, k, html.EscapeString(v))
+	}
+
+	from := v.From
+	if v.Name != "" {
+		from = (&mail.Address{Name: v.Name, Address: v.From}).String()
+	}
+
+	return &CustomVerificationPreview{
+		From:    from,
+		Subject: strings.NewReplacer(text...).Replace(v.Subject),
+		Html:    strings.NewReplacer(escaped...).Replace(v.Html),
+		Text:    strings.NewReplacer(text...).Replace(v.Text),
+	}, nil
+}
+
+// encode adds every field, so that a template can be cleared by updating it
+// with empty values.
+func (v CustomVerification) encode(params url.Values) {
+	for k, vv := range map[string]string{
+		"custom_verification[name]":     v.Name,
+		"custom_verification[from]":     v.From,
+		"custom_verification[subject]":  v.Subject,
+		"custom_verification[html]":     v.Html,
+		"custom_verification[text]":     v.Text,
+		"custom_verification[redirect]": v.RedirectUrl,
+	} {
+		params.Add(k, vv)
+	}
+}
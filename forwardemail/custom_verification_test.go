@@ -0,0 +1,70 @@
+package forwardemail
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCustomVerification_Preview(t *testing.T) {
+	tests := []struct {
+		name         string
+		verification CustomVerification
+		want         *CustomVerificationPreview
+		wantErr      string
+	}{
+		{
+			name: "ok",
+			verification: CustomVerification{
+				Name:    "Stark Industries",
+				From:    "no-reply@stark.com",
+				Subject: "FROM_EMAIL wants to forward mail to TO_EMAIL",
+				Html:    `<p>Hi TO_EMAIL,</p><a href="VERIFICATION_LINK">Confirm</a>`,
+				Text:    "Confirm: VERIFICATION_LINK",
+			},
+			want: &CustomVerificationPreview{
+				From:    `"Stark Industries" <no-reply@stark.com>`,
+				Subject: "sender@example.com wants to forward mail to recipient@example.com",
+				Html:    `<p>Hi recipient@example.com,</p><a href="https://forwardemail.net/v/sample-verification-token">Confirm</a>`,
+				Text:    "Confirm: https://forwardemail.net/v/sample-verification-token",
+			},
+		},
+		{
+			name: "text only",
+			verification: CustomVerification{
+				From: "no-reply@stark.com",
+				Text: "VERIFICATION_LINK",
+			},
+			want: &CustomVerificationPreview{
+				From: "no-reply@stark.com",
+				Text: "https://forwardemail.net/v/sample-verification-token",
+			},
+		},
+		{
+			name: "missing link",
+			verification: CustomVerification{
+				Html: "<p>Welcome to Stark Industries</p>",
+			},
+			wantErr: "custom verification: template has no VERIFICATION_LINK placeholder",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.verification.Preview()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
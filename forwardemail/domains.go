@@ -1,9 +1,11 @@
 package forwardemail
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"iter"
 	"net/url"
 	"strconv"
 	"strings"
@@ -11,25 +13,59 @@ import (
 )
 
 type Domain struct {
-	HasAdultContentProtection bool      `json:"has_adult_content_protection"`
-	HasPhishingProtection     bool      `json:"has_phishing_protection"`
-	HasExecutableProtection   bool      `json:"has_executable_protection"`
-	HasVirusProtection        bool      `json:"has_virus_protection"`
-	IsCatchallRegexDisabled   bool      `json:"is_catchall_regex_disabled"`
-	Plan                      string    `json:"plan"`
-	MaxRecipientsPerAlias     int       `json:"max_recipients_per_alias"`
-	SmtpPort                  string    `json:"smtp_port"`
-	Name                      string    `json:"name"`
-	HasMxRecord               bool      `json:"has_mx_record"`
-	HasTxtRecord              bool      `json:"has_txt_record"`
-	HasRecipientVerification  bool      `json:"has_recipient_verification"`
-	HasCustomVerification     bool      `json:"has_custom_verification"`
-	VerificationRecord        string    `json:"verification_record"`
-	Id                        string    `json:"id"`
-	Object                    string    `json:"object"`
-	CreatedAt                 time.Time `json:"created_at"`
-	UpdatedAt                 time.Time `json:"updated_at"`
-	Link                      string    `json:"link"`
+	HasAdultContentProtection bool               `json:"has_adult_content_protection"`
+	HasPhishingProtection     bool               `json:"has_phishing_protection"`
+	HasExecutableProtection   bool               `json:"has_executable_protection"`
+	HasVirusProtection        bool               `json:"has_virus_protection"`
+	IsCatchallRegexDisabled   bool               `json:"is_catchall_regex_disabled"`
+	Plan                      string             `json:"plan"`
+	MaxRecipientsPerAlias     int                `json:"max_recipients_per_alias"`
+	SmtpPort                  string             `json:"smtp_port"`
+	Name                      string             `json:"name"`
+	AliasCount                int                `json:"alias_count"`
+	HasMxRecord               bool               `json:"has_mx_record"`
+	HasTxtRecord              bool               `json:"has_txt_record"`
+	HasSmtp                   bool               `json:"has_smtp"`
+	HasDkimRecord             bool               `json:"has_dkim_record"`
+	HasReturnPathRecord       bool               `json:"has_return_path_record"`
+	HasDmarcRecord            bool               `json:"has_dmarc_record"`
+	DkimKeySelector           string             `json:"dkim_key_selector"`
+	DkimPublicKey             string             `json:"dkim_public_key"`
+	ReturnPath                string             `json:"return_path"`
+	IgnoreMxCheck             bool               `json:"ignore_mx_check"`
+	RetentionDays             int                `json:"retention_days"`
+	BounceWebhook             string             `json:"bounce_webhook"`
+	WebhookKey                string             `json:"webhook_key"`
+	Allowlist                 []string           `json:"allowlist"`
+	Denylist                  []string           `json:"denylist"`
+	HasRecipientVerification  bool               `json:"has_recipient_verification"`
+	HasCustomVerification     bool               `json:"has_custom_verification"`
+	CustomVerification        CustomVerification `json:"custom_verification"`
+	VerificationRecord        string             `json:"verification_record"`
+	Id                        string             `json:"id"`
+	Object                    string             `json:"object"`
+	CreatedAt                 time.Time          `json:"created_at"`
+	UpdatedAt                 time.Time          `json:"updated_at"`
+	LastAllowlistSyncAt       time.Time          `json:"last_allowlist_sync_at"`
+	Link                      string             `json:"link"`
+	Members                   []Member           `json:"members"`
+	Invites                   []Invite           `json:"invites"`
+}
+
+// ListDomainsOptions filters, sorts and selects a page of domains. Zero
+// values use the API defaults.
+type ListDomainsOptions struct {
+	Page  int
+	Limit int
+
+	Name string
+	// Alias and Recipient keep only the domains with a matching alias name
+	// or forwarding recipient.
+	Alias     string
+	Recipient string
+	// Sort is a field name such as "created_at", prefixed with "-" for
+	// descending order.
+	Sort string
 }
 
 type DomainParameters struct {
@@ -38,10 +74,27 @@ type DomainParameters struct {
 	HasExecutableProtection   *bool
 	HasVirusProtection        *bool
 	HasRecipientVerification  *bool
+	IsCatchallRegexDisabled   *bool
+	IgnoreMxCheck             *bool
+	// HasSmtp enables outbound SMTP for the domain.
+	HasSmtp               *bool
+	SmtpPort              *string
+	MaxRecipientsPerAlias *int
+	RetentionDays         *int
+	BounceWebhook         *string
+	WebhookKey            *string
+	Allowlist             *[]string
+	Denylist              *[]string
+	CustomVerification    *CustomVerification
 }
 
 func (c *Client) GetDomains() ([]Domain, error) {
-	req, err := c.newRequest("GET", "/v1/domains")
+	return c.GetDomainsContext(context.Background())
+}
+
+// GetDomainsContext is like GetDomains but carries ctx with the request.
+func (c *Client) GetDomainsContext(ctx context.Context) ([]Domain, error) {
+	req, err := c.newRequest(ctx, "GET", "/v1/domains")
 	if err != nil {
 		return nil, err
 	}
@@ -61,8 +114,38 @@ func (c *Client) GetDomains() ([]Domain, error) {
 	return items, nil
 }
 
+// ListDomains returns a single page of domains together with the total
+// counts. Use AllDomains to go through every page.
+func (c *Client) ListDomains(options ListDomainsOptions) (*Page[Domain], error) {
+	return c.ListDomainsContext(context.Background(), options)
+}
+
+// ListDomainsContext is like ListDomains but carries ctx with the request.
+func (c *Client) ListDomainsContext(ctx context.Context, options ListDomainsOptions) (*Page[Domain], error) {
+	return getPage[Domain](ctx, c, "/v1/domains", options.encode())
+}
+
+// AllDomains iterates over the domains, fetching pages lazily starting from
+// options.Page.
+func (c *Client) AllDomains(options ListDomainsOptions) iter.Seq2[Domain, error] {
+	return c.AllDomainsContext(context.Background(), options)
+}
+
+// AllDomainsContext is like AllDomains but carries ctx with the request.
+func (c *Client) AllDomainsContext(ctx context.Context, options ListDomainsOptions) iter.Seq2[Domain, error] {
+	return all(options.Page, func(page int) (*Page[Domain], error) {
+		options.Page = page
+		return c.ListDomainsContext(ctx, options)
+	})
+}
+
 func (c *Client) GetDomain(name string) (*Domain, error) {
-	req, err := c.newRequest("GET", fmt.Sprintf("/v1/domains/%s", name))
+	return c.GetDomainContext(context.Background(), name)
+}
+
+// GetDomainContext is like GetDomain but carries ctx with the request.
+func (c *Client) GetDomainContext(ctx context.Context, name string) (*Domain, error) {
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("/v1/domains/%s", name))
 	if err != nil {
 		return nil, err
 	}
@@ -83,28 +166,20 @@ func (c *Client) GetDomain(name string) (*Domain, error) {
 }
 
 func (c *Client) CreateDomain(name string, parameters DomainParameters) (*Domain, error) {
-	req, err := c.newRequest("POST", "/v1/domains")
+	return c.CreateDomainContext(context.Background(), name, parameters)
+}
+
+// CreateDomainContext is like CreateDomain but carries ctx with the request.
+func (c *Client) CreateDomainContext(ctx context.Context, name string, parameters DomainParameters) (*Domain, error) {
+	req, err := c.newRequest(ctx, "POST", "/v1/domains")
 	if err != nil {
 		return nil, err
 	}
 
-	params := url.Values{}
+	params := parameters.encode()
 	params.Add("domain", name)
 
-	for k, v := range map[string]*bool{
-		"has_adult_content_protection": parameters.HasAdultContentProtection,
-		"has_phishing_protection":      parameters.HasPhishingProtection,
-		"has_executable_protection":    parameters.HasExecutableProtection,
-		"has_virus_protection":         parameters.HasVirusProtection,
-		"has_recipient_verification":   parameters.HasRecipientVerification,
-	} {
-		if v != nil {
-			params.Add(k, strconv.FormatBool(*v))
-		}
-	}
-
-	req.Body = io.NopCloser(strings.NewReader(params.Encode()))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	setFormBody(req, params)
 
 	res, err := c.doRequest(req)
 	if err != nil {
@@ -122,28 +197,20 @@ func (c *Client) CreateDomain(name string, parameters DomainParameters) (*Domain
 }
 
 func (c *Client) UpdateDomain(name string, parameters DomainParameters) (*Domain, error) {
-	req, err := c.newRequest("PUT", fmt.Sprintf("/v1/domains/%s", name))
+	return c.UpdateDomainContext(context.Background(), name, parameters)
+}
+
+// UpdateDomainContext is like UpdateDomain but carries ctx with the request.
+func (c *Client) UpdateDomainContext(ctx context.Context, name string, parameters DomainParameters) (*Domain, error) {
+	req, err := c.newRequest(ctx, "PUT", fmt.Sprintf("/v1/domains/%s", name))
 	if err != nil {
 		return nil, err
 	}
 
-	params := url.Values{}
+	params := parameters.encode()
 	params.Add("domain", name)
 
-	for k, v := range map[string]*bool{
-		"has_adult_content_protection": parameters.HasAdultContentProtection,
-		"has_phishing_protection":      parameters.HasPhishingProtection,
-		"has_executable_protection":    parameters.HasExecutableProtection,
-		"has_virus_protection":         parameters.HasVirusProtection,
-		"has_recipient_verification":   parameters.HasRecipientVerification,
-	} {
-		if v != nil {
-			params.Add(k, strconv.FormatBool(*v))
-		}
-	}
-
-	req.Body = io.NopCloser(strings.NewReader(params.Encode()))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	setFormBody(req, params)
 
 	res, err := c.doRequest(req)
 	if err != nil {
@@ -161,7 +228,12 @@ func (c *Client) UpdateDomain(name string, parameters DomainParameters) (*Domain
 }
 
 func (c *Client) DeleteDomain(name string) error {
-	req, err := c.newRequest("DELETE", fmt.Sprintf("/v1/domains/%s", name))
+	return c.DeleteDomainContext(context.Background(), name)
+}
+
+// DeleteDomainContext is like DeleteDomain but carries ctx with the request.
+func (c *Client) DeleteDomainContext(ctx context.Context, name string) error {
+	req, err := c.newRequest(ctx, "DELETE", fmt.Sprintf("/v1/domains/%s", name))
 	if err != nil {
 		return err
 	}
@@ -173,3 +245,198 @@ func (c *Client) DeleteDomain(name string) error {
 
 	return nil
 }
+
+func (o ListDomainsOptions) encode() url.Values {
+	params := url.Values{}
+
+	encodePagination(params, o.Page, o.Limit)
+
+	for k, v := range map[string]string{
+		"name":      o.Name,
+		"alias":     o.Alias,
+		"recipient": o.Recipient,
+		"sort":      o.Sort,
+	} {
+		if v != "" {
+			params.Add(k, v)
+		}
+	}
+
+	return params
+}
+
+func (p DomainParameters) encode() url.Values {
+	params := url.Values{}
+
+	for k, v := range map[string]*bool{
+		"has_adult_content_protection": p.HasAdultContentProtection,
+		"has_phishing_protection":      p.HasPhishingProtection,
+		"has_executable_protection":    p.HasExecutableProtection,
+		"has_virus_protection":         p.HasVirusProtection,
+		"has_recipient_verification":   p.HasRecipientVerification,
+		"is_catchall_regex_disabled":   p.IsCatchallRegexDisabled,
+		"ignore_mx_check":              p.IgnoreMxCheck,
+		"has_smtp":                     p.HasSmtp,
+	} {
+		if v != nil {
+			params.Add(k, strconv.FormatBool(*v))
+		}
+	}
+
+	for k, v := range map[string]*string{
+		"smtp_port":      p.SmtpPort,
+		"bounce_webhook": p.BounceWebhook,
+		"webhook_key":    p.WebhookKey,
+	} {
+		if v != nil {
+			params.Add(k, *v)
+		}
+	}
+
+	for k, v := range map[string]*int{
+		"max_recipients_per_alias": p.MaxRecipientsPerAlias,
+		"retention_days":           p.RetentionDays,
+	} {
+		if v != nil {
+			params.Add(k, strconv.Itoa(*v))
+		}
+	}
+
+	encodeList(params, "allowlist", p.Allowlist)
+	encodeList(params, "denylist", p.Denylist)
+
+	if p.CustomVerification != nil {
+		p.CustomVerification.encode(params)
+	}
+
+	return params
+}
+
+const (
+	DnsRecordKindMx         = "mx"
+	DnsRecordKindTxt        = "txt"
+	DnsRecordKindDkim       = "dkim"
+	DnsRecordKindReturnPath = "return_path"
+	DnsRecordKindDmarc      = "dmarc"
+)
+
+// DnsRecordCheck tells whether a single record is in place, taken from the
+// has_*_record flags of the domain. The flags do not say whether a record
+// that is not in place is missing or has a wrong value; Message on the
+// DomainVerification does.
+type DnsRecordCheck struct {
+	Kind     string
+	Verified bool
+}
+
+// DomainVerification is the outcome of a verification request. Message is
+// the explanation from the API, which on failure lists what to fix. Records
+// is nil when the domain could not be read back after the verification.
+type DomainVerification struct {
+	Verified bool
+	Message  string
+	Records  []DnsRecordCheck
+}
+
+// Failed returns the records that are not in place.
+func (v DomainVerification) Failed() []DnsRecordCheck {
+	var failed []DnsRecordCheck
+
+	for _, r := range v.Records {
+		if !r.Verified {
+			failed = append(failed, r)
+		}
+	}
+
+	return failed
+}
+
+// VerifyDomainRecords asks Forward Email to check the MX and TXT records of
+// the domain. Records that are not in place are not an error: the result is
+// then unverified and its Message says what to fix. An error is returned for
+// anything else, such as an unknown domain or an invalid API key.
+func (c *Client) VerifyDomainRecords(name string) (*DomainVerification, error) {
+	return c.VerifyDomainRecordsContext(context.Background(), name)
+}
+
+// VerifyDomainRecordsContext is like VerifyDomainRecords but carries ctx with
+// the request.
+func (c *Client) VerifyDomainRecordsContext(ctx context.Context, name string) (*DomainVerification, error) {
+	return c.verifyDomain(ctx, name, "verify-records", DnsRecordKindMx, DnsRecordKindTxt)
+}
+
+// VerifyDomainSMTPRecords is like VerifyDomainRecords for the DKIM,
+// return-path and DMARC records needed to send email.
+func (c *Client) VerifyDomainSMTPRecords(name string) (*DomainVerification, error) {
+	return c.VerifyDomainSMTPRecordsContext(context.Background(), name)
+}
+
+// VerifyDomainSMTPRecordsContext is like VerifyDomainSMTPRecords but carries
+// ctx with the request.
+func (c *Client) VerifyDomainSMTPRecordsContext(ctx context.Context, name string) (*DomainVerification, error) {
+	return c.verifyDomain(ctx, name, "verify-smtp", DnsRecordKindDkim, DnsRecordKindReturnPath, DnsRecordKindDmarc)
+}
+
+// verifyDomain treats a validation error as a failed verification, then reads
+// the domain back to tell which of the records are in place. The result is
+// kept without Records if the domain cannot be read.
+func (c *Client) verifyDomain(ctx context.Context, name, check string, kinds ...string) (*DomainVerification, error) {
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("/v1/domains/%s/%s", name, check))
+	if err != nil {
+		return nil, err
+	}
+
+	var item DomainVerification
+
+	res, err := c.doRequest(req)
+
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		item.Verified = true
+		item.Message = verificationMessage(res)
+	case errors.As(err, &apiErr) && errors.Is(err, ErrValidation):
+		item.Message = apiErr.Message
+		if item.Message == "" {
+			item.Message = verificationMessage(apiErr.Body)
+		}
+	default:
+		return nil, err
+	}
+
+	domain, err := c.GetDomainContext(ctx, name)
+	if err != nil {
+		return &item, nil
+	}
+
+	flags := map[string]bool{
+		DnsRecordKindMx:         domain.HasMxRecord,
+		DnsRecordKindTxt:        domain.HasTxtRecord,
+		DnsRecordKindDkim:       domain.HasDkimRecord,
+		DnsRecordKindReturnPath: domain.HasReturnPathRecord,
+		DnsRecordKindDmarc:      domain.HasDmarcRecord,
+	}
+	for _, kind := range kinds {
+		item.Records = append(item.Records, DnsRecordCheck{Kind: kind, Verified: flags[kind]})
+	}
+
+	return &item, nil
+}
+
+// verificationMessage accepts a JSON string, an object with a message field
+// or plain text.
+func verificationMessage(body []byte) string {
+	var message string
+	if json.Unmarshal(body, &message) == nil {
+		return message
+	}
+
+	var fields struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &fields) == nil {
+		return fields.Message
+	}
+
+	return strings.TrimSpace(string(body))
+}
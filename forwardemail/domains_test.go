@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -60,6 +62,7 @@ func TestClient_GetDomain(t *testing.T) {
 				Object:                    "domain",
 				CreatedAt:                 parseTime("2023-09-21T20:18:24.790Z"),
 				UpdatedAt:                 parseTime("2023-10-07T21:21:01.992Z"),
+				LastAllowlistSyncAt:       parseTime("2023-10-07T13:06:02.630Z"),
 				Link:                      "https://forwardemail.net/my-account/domains/stark.com",
 			},
 		},
@@ -158,6 +161,7 @@ func TestClient_GetDomains(t *testing.T) {
 					Object:                    "domain",
 					CreatedAt:                 parseTime("2023-09-21T20:18:24.790Z"),
 					UpdatedAt:                 parseTime("2023-10-07T21:21:01.992Z"),
+					LastAllowlistSyncAt:       parseTime("2023-10-07T13:06:02.630Z"),
 					Link:                      "https://forwardemail.net/my-account/domains/stark.com",
 				},
 				{
@@ -176,6 +180,7 @@ func TestClient_GetDomains(t *testing.T) {
 					Object:                    "domain",
 					CreatedAt:                 parseTime("2023-04-04T12:13:55.723Z"),
 					UpdatedAt:                 parseTime("2023-11-03T22:22:02.724Z"),
+					LastAllowlistSyncAt:       parseTime("2023-11-03T22:23:08.123Z"),
 					Link:                      "https://forwardemail.net/my-account/domains/rhodes.com",
 				},
 			},
@@ -201,16 +206,203 @@ func TestClient_GetDomains(t *testing.T) {
 	}
 }
 
+func TestClient_ListDomains(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   ListDomainsOptions
+		headers   map[string]string
+		response  string
+		wantQuery url.Values
+		want      *Page[Domain]
+	}{
+		{
+			name:      "no headers",
+			response:  `[{"name": "stark.com"}]`,
+			wantQuery: url.Values{},
+			want: &Page[Domain]{
+				Items: []Domain{{Name: "stark.com"}},
+				Page:  1,
+			},
+		},
+		{
+			name:    "ok",
+			options: ListDomainsOptions{Page: 2, Limit: 1},
+			headers: map[string]string{
+				"X-Page-Count": "3",
+				"X-Item-Count": "3",
+				"Link":         `<https://api.forwardemail.net/v1/domains?page=3&limit=1>; rel="next"`,
+			},
+			response: `[{"name": "rhodes.com"}]`,
+			wantQuery: url.Values{
+				"page":  {"2"},
+				"limit": {"1"},
+			},
+			want: &Page[Domain]{
+				Items:     []Domain{{Name: "rhodes.com"}},
+				Page:      2,
+				PageCount: 3,
+				ItemCount: 3,
+				NextPage:  3,
+			},
+		},
+		{
+			name: "filters",
+			options: ListDomainsOptions{
+				Name:      "stark",
+				Alias:     "tony",
+				Recipient: "bob@corp.com",
+				Sort:      "name",
+			},
+			response: `[{"name": "stark.com"}]`,
+			wantQuery: url.Values{
+				"name":      {"stark"},
+				"alias":     {"tony"},
+				"recipient": {"bob@corp.com"},
+				"sort":      {"name"},
+			},
+			want: &Page[Domain]{
+				Items: []Domain{{Name: "stark.com"}},
+				Page:  1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotQuery url.Values
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotQuery = r.URL.Query()
+				for k, v := range tt.headers {
+					w.Header().Set(k, v)
+				}
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got, _ := c.ListDomains(tt.options)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantQuery, gotQuery); diff != "" {
+				t.Fatalf("queries are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_AllDomains(t *testing.T) {
+	pages := map[string]string{
+		"1": `[{"name": "stark.com"}, {"name": "rhodes.com"}]`,
+		"2": `[{"name": "potts.com"}, {"name": "hogan.com"}]`,
+		"3": `[{"name": "banner.com"}]`,
+	}
+
+	tests := []struct {
+		name      string
+		options   ListDomainsOptions
+		stopAfter int
+		failPage  string
+		want      []string
+		wantErr   error
+		wantPages []string
+	}{
+		{
+			name:      "every page",
+			options:   ListDomainsOptions{Limit: 2},
+			want:      []string{"stark.com", "rhodes.com", "potts.com", "hogan.com", "banner.com"},
+			wantPages: []string{"1", "2", "3"},
+		},
+		{
+			name:      "from page",
+			options:   ListDomainsOptions{Page: 2, Limit: 2},
+			want:      []string{"potts.com", "hogan.com", "banner.com"},
+			wantPages: []string{"2", "3"},
+		},
+		{
+			name:      "stop early",
+			options:   ListDomainsOptions{Limit: 2},
+			stopAfter: 3,
+			want:      []string{"stark.com", "rhodes.com", "potts.com"},
+			wantPages: []string{"1", "2"},
+		},
+		{
+			name:      "error",
+			options:   ListDomainsOptions{Limit: 2},
+			failPage:  "2",
+			want:      []string{"stark.com", "rhodes.com"},
+			wantErr:   &APIError{StatusCode: 500, Body: []byte("oh no")},
+			wantPages: []string{"1", "2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPages []string
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				page := r.URL.Query().Get("page")
+				gotPages = append(gotPages, page)
+
+				if page == tt.failPage {
+					w.WriteHeader(http.StatusInternalServerError)
+					fmt.Fprintf(w, "oh no")
+					return
+				}
+
+				w.Header().Set("X-Page-Count", "3")
+				w.Header().Set("X-Item-Count", "5")
+				fmt.Fprintf(w, pages[page])
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			var got []string
+			var gotErr error
+
+			for domain, err := range c.AllDomains(tt.options) {
+				if err != nil {
+					gotErr = err
+					break
+				}
+				got = append(got, domain.Name)
+				if len(got) == tt.stopAfter {
+					break
+				}
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantErr, gotErr, cmp.Comparer(equateErrorMessage)); diff != "" {
+				t.Fatalf("errors are not the same %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantPages, gotPages); diff != "" {
+				t.Fatalf("fetched pages are not the same %s", diff)
+			}
+		})
+	}
+}
+
 func TestClient_CreateDomain(t *testing.T) {
 	tests := []struct {
 		name       string
 		domain     string
 		parameters DomainParameters
 		response   string
+		wantForm   url.Values
 		want       *Domain
 	}{
 		{
-			name: "no data",
+			name:     "no data",
+			wantForm: url.Values{"domain": {""}},
 		},
 		{
 			name:   "ok",
@@ -244,6 +436,14 @@ func TestClient_CreateDomain(t *testing.T) {
 				  "last_allowlist_sync_at": "2023-10-07T13:06:02.630Z",
 				  "link": "https://forwardemail.net/my-account/domains/stark.com"
 			}`,
+			wantForm: url.Values{
+				"domain":                       {"stark.com"},
+				"has_adult_content_protection": {"true"},
+				"has_phishing_protection":      {"true"},
+				"has_executable_protection":    {"true"},
+				"has_virus_protection":         {"true"},
+				"has_recipient_verification":   {"true"},
+			},
 			want: &Domain{
 				HasAdultContentProtection: true,
 				HasPhishingProtection:     true,
@@ -260,6 +460,7 @@ func TestClient_CreateDomain(t *testing.T) {
 				Object:                    "domain",
 				CreatedAt:                 parseTime("2023-09-21T20:18:24.790Z"),
 				UpdatedAt:                 parseTime("2023-10-07T21:21:01.992Z"),
+				LastAllowlistSyncAt:       parseTime("2023-10-07T13:06:02.630Z"),
 				Link:                      "https://forwardemail.net/my-account/domains/stark.com",
 			},
 		},
@@ -267,7 +468,11 @@ func TestClient_CreateDomain(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			var gotForm url.Values
+
 			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				gotForm = r.PostForm
 				fmt.Fprintf(w, tt.response)
 			}))
 			defer svr.Close()
@@ -280,6 +485,9 @@ func TestClient_CreateDomain(t *testing.T) {
 			if diff := cmp.Diff(tt.want, got); diff != "" {
 				t.Fatalf("values are not the same %s", diff)
 			}
+			if diff := cmp.Diff(tt.wantForm, gotForm); diff != "" {
+				t.Fatalf("form values are not the same %s", diff)
+			}
 		})
 	}
 }
@@ -290,10 +498,12 @@ func TestClient_UpdateDomain(t *testing.T) {
 		domain     string
 		parameters DomainParameters
 		response   string
+		wantForm   url.Values
 		want       *Domain
 	}{
 		{
-			name: "no data",
+			name:     "no data",
+			wantForm: url.Values{"domain": {""}},
 		},
 		{
 			name:   "ok",
@@ -327,6 +537,14 @@ func TestClient_UpdateDomain(t *testing.T) {
 				  "last_allowlist_sync_at": "2023-10-07T13:06:02.630Z",
 				  "link": "https://forwardemail.net/my-account/domains/stark.com"
 			}`,
+			wantForm: url.Values{
+				"domain":                       {"stark.com"},
+				"has_adult_content_protection": {"true"},
+				"has_phishing_protection":      {"true"},
+				"has_executable_protection":    {"true"},
+				"has_virus_protection":         {"true"},
+				"has_recipient_verification":   {"true"},
+			},
 			want: &Domain{
 				HasAdultContentProtection: true,
 				HasPhishingProtection:     true,
@@ -343,14 +561,181 @@ func TestClient_UpdateDomain(t *testing.T) {
 				Object:                    "domain",
 				CreatedAt:                 parseTime("2023-09-21T20:18:24.790Z"),
 				UpdatedAt:                 parseTime("2023-10-07T21:21:01.992Z"),
+				LastAllowlistSyncAt:       parseTime("2023-10-07T13:06:02.630Z"),
 				Link:                      "https://forwardemail.net/my-account/domains/stark.com",
 			},
 		},
+		{
+			name:   "all settings",
+			domain: "stark.com",
+			parameters: DomainParameters{
+				IsCatchallRegexDisabled: pointBool(true),
+				IgnoreMxCheck:           pointBool(true),
+				HasSmtp:                 pointBool(true),
+				SmtpPort:                pointString("2525"),
+				MaxRecipientsPerAlias:   pointInt(25),
+				RetentionDays:           pointInt(7),
+				BounceWebhook:           pointString("https://hooks.stark.com/bounces"),
+				WebhookKey:              pointString("jarvis-signing-key"),
+				Allowlist:               pointSliceOfStrings([]string{"rhodes.com", "pepper@potts.com"}),
+				Denylist:                pointSliceOfStrings([]string{"hammer.com"}),
+			},
+			response: `{
+				  "is_catchall_regex_disabled": true,
+				  "plan": "enhanced_protection",
+				  "max_recipients_per_alias": 25,
+				  "smtp_port": "2525",
+				  "name": "stark.com",
+				  "alias_count": 12,
+				  "has_mx_record": true,
+				  "has_txt_record": true,
+				  "has_smtp": true,
+				  "has_dkim_record": true,
+				  "has_return_path_record": true,
+				  "has_dmarc_record": false,
+				  "dkim_key_selector": "fe-bb3a9a40",
+				  "dkim_public_key": "MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQC",
+				  "return_path": "fe-bounces",
+				  "ignore_mx_check": true,
+				  "retention_days": 7,
+				  "bounce_webhook": "https://hooks.stark.com/bounces",
+				  "webhook_key": "jarvis-signing-key",
+				  "allowlist": ["rhodes.com", "pepper@potts.com"],
+				  "denylist": ["hammer.com"],
+				  "id": "15ff615b6180f1fc7faf40e6",
+				  "object": "domain",
+				  "last_allowlist_sync_at": "2023-10-07T13:06:02.630Z"
+			}`,
+			wantForm: url.Values{
+				"domain":                     {"stark.com"},
+				"is_catchall_regex_disabled": {"true"},
+				"ignore_mx_check":            {"true"},
+				"has_smtp":                   {"true"},
+				"smtp_port":                  {"2525"},
+				"max_recipients_per_alias":   {"25"},
+				"retention_days":             {"7"},
+				"bounce_webhook":             {"https://hooks.stark.com/bounces"},
+				"webhook_key":                {"jarvis-signing-key"},
+				"allowlist[]":                {"rhodes.com", "pepper@potts.com"},
+				"denylist[]":                 {"hammer.com"},
+			},
+			want: &Domain{
+				IsCatchallRegexDisabled: true,
+				Plan:                    "enhanced_protection",
+				MaxRecipientsPerAlias:   25,
+				SmtpPort:                "2525",
+				Name:                    "stark.com",
+				AliasCount:              12,
+				HasMxRecord:             true,
+				HasTxtRecord:            true,
+				HasSmtp:                 true,
+				HasDkimRecord:           true,
+				HasReturnPathRecord:     true,
+				DkimKeySelector:         "fe-bb3a9a40",
+				DkimPublicKey:           "MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQC",
+				ReturnPath:              "fe-bounces",
+				IgnoreMxCheck:           true,
+				RetentionDays:           7,
+				BounceWebhook:           "https://hooks.stark.com/bounces",
+				WebhookKey:              "jarvis-signing-key",
+				Allowlist:               []string{"rhodes.com", "pepper@potts.com"},
+				Denylist:                []string{"hammer.com"},
+				Id:                      "15ff615b6180f1fc7faf40e6",
+				Object:                  "domain",
+				LastAllowlistSyncAt:     parseTime("2023-10-07T13:06:02.630Z"),
+			},
+		},
+		{
+			name:   "clear lists",
+			domain: "stark.com",
+			parameters: DomainParameters{
+				Allowlist: pointSliceOfStrings([]string{}),
+				Denylist:  pointSliceOfStrings([]string{}),
+			},
+			response: `{
+				  "name": "stark.com",
+				  "allowlist": [],
+				  "denylist": [],
+				  "id": "15ff615b6180f1fc7faf40e6",
+				  "object": "domain"
+			}`,
+			wantForm: url.Values{
+				"domain":    {"stark.com"},
+				"allowlist": {""},
+				"denylist":  {""},
+			},
+			want: &Domain{
+				Name:      "stark.com",
+				Allowlist: []string{},
+				Denylist:  []string{},
+				Id:        "15ff615b6180f1fc7faf40e6",
+				Object:    "domain",
+			},
+		},
+		{
+			name:   "custom verification",
+			domain: "stark.com",
+			parameters: DomainParameters{
+				HasRecipientVerification: pointBool(true),
+				CustomVerification: &CustomVerification{
+					Name:        "Stark Industries",
+					From:        "no-reply@stark.com",
+					Subject:     "Confirm forwarding to TO_EMAIL",
+					Html:        "<a href=\"VERIFICATION_LINK\">Confirm</a>",
+					Text:        "Confirm: VERIFICATION_LINK",
+					RedirectUrl: "https://stark.com/verified",
+				},
+			},
+			response: `{
+				  "name": "stark.com",
+				  "has_recipient_verification": true,
+				  "has_custom_verification": true,
+				  "custom_verification": {
+				    "name": "Stark Industries",
+				    "from": "no-reply@stark.com",
+				    "subject": "Confirm forwarding to TO_EMAIL",
+				    "html": "<a href=\"VERIFICATION_LINK\">Confirm</a>",
+				    "text": "Confirm: VERIFICATION_LINK",
+				    "redirect": "https://stark.com/verified"
+				  },
+				  "id": "15ff615b6180f1fc7faf40e6",
+				  "object": "domain"
+			}`,
+			wantForm: url.Values{
+				"domain":                        {"stark.com"},
+				"has_recipient_verification":    {"true"},
+				"custom_verification[name]":     {"Stark Industries"},
+				"custom_verification[from]":     {"no-reply@stark.com"},
+				"custom_verification[subject]":  {"Confirm forwarding to TO_EMAIL"},
+				"custom_verification[html]":     {`<a href="VERIFICATION_LINK">Confirm</a>`},
+				"custom_verification[text]":     {"Confirm: VERIFICATION_LINK"},
+				"custom_verification[redirect]": {"https://stark.com/verified"},
+			},
+			want: &Domain{
+				Name:                     "stark.com",
+				HasRecipientVerification: true,
+				HasCustomVerification:    true,
+				CustomVerification: CustomVerification{
+					Name:        "Stark Industries",
+					From:        "no-reply@stark.com",
+					Subject:     "Confirm forwarding to TO_EMAIL",
+					Html:        `<a href="VERIFICATION_LINK">Confirm</a>`,
+					Text:        "Confirm: VERIFICATION_LINK",
+					RedirectUrl: "https://stark.com/verified",
+				},
+				Id:     "15ff615b6180f1fc7faf40e6",
+				Object: "domain",
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			var gotForm url.Values
+
 			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				gotForm = r.PostForm
 				fmt.Fprintf(w, tt.response)
 			}))
 			defer svr.Close()
@@ -363,6 +748,9 @@ func TestClient_UpdateDomain(t *testing.T) {
 			if diff := cmp.Diff(tt.want, got); diff != "" {
 				t.Fatalf("values are not the same %s", diff)
 			}
+			if diff := cmp.Diff(tt.wantForm, gotForm); diff != "" {
+				t.Fatalf("form values are not the same %s", diff)
+			}
 		})
 	}
 }
@@ -391,7 +779,7 @@ func TestClient_DeleteDomain(t *testing.T) {
 				code: http.StatusInternalServerError,
 				body: "oh no",
 			},
-			want: fmt.Errorf("status: 500, body: oh no"),
+			want: &APIError{StatusCode: 500, Body: []byte("oh no")},
 		},
 	}
 
@@ -415,6 +803,162 @@ func TestClient_DeleteDomain(t *testing.T) {
 	}
 }
 
+func TestClient_VerifyDomainRecords(t *testing.T) {
+	tests := []struct {
+		name     string
+		domain   string
+		status   int
+		response string
+		record   string
+		wantPath string
+		want     *DomainVerification
+		wantErr  string
+	}{
+		{
+			name:     "no data",
+			wantPath: "/v1/domains//verify-records",
+			want:     &DomainVerification{Verified: true},
+		},
+		{
+			name:     "verified",
+			domain:   "stark.com",
+			status:   http.StatusOK,
+			response: `"Domain's DNS records have been verified."`,
+			record:   `{"name": "stark.com", "has_mx_record": true, "has_txt_record": true}`,
+			wantPath: "/v1/domains/stark.com/verify-records",
+			want: &DomainVerification{
+				Verified: true,
+				Message:  "Domain's DNS records have been verified.",
+				Records: []DnsRecordCheck{
+					{Kind: DnsRecordKindMx, Verified: true},
+					{Kind: DnsRecordKindTxt, Verified: true},
+				},
+			},
+		},
+		{
+			name:     "missing txt",
+			domain:   "stark.com",
+			status:   http.StatusBadRequest,
+			response: `{"statusCode": 400, "error": "Bad Request", "message": "Please configure the TXT record forward-email-site-verification=v8O0S8JjRv."}`,
+			record:   `{"name": "stark.com", "has_mx_record": true, "has_txt_record": false}`,
+			wantPath: "/v1/domains/stark.com/verify-records",
+			want: &DomainVerification{
+				Message: "Please configure the TXT record forward-email-site-verification=v8O0S8JjRv.",
+				Records: []DnsRecordCheck{
+					{Kind: DnsRecordKindMx, Verified: true},
+					{Kind: DnsRecordKindTxt, Verified: false},
+				},
+			},
+		},
+		{
+			name:     "domain unavailable",
+			domain:   "stark.com",
+			status:   http.StatusBadRequest,
+			response: `{"statusCode": 400, "error": "Bad Request", "message": "Please configure the MX records."}`,
+			record:   `{"statusCode": 500, "error": "Internal Server Error"}`,
+			wantPath: "/v1/domains/stark.com/verify-records",
+			want: &DomainVerification{
+				Message: "Please configure the MX records.",
+			},
+		},
+		{
+			name:     "unknown domain",
+			domain:   "stark.com",
+			status:   http.StatusNotFound,
+			response: `{"statusCode": 404, "error": "Not Found", "message": "Domain does not exist."}`,
+			wantPath: "/v1/domains/stark.com/verify-records",
+			wantErr:  `status: 404, body: {"statusCode": 404, "error": "Not Found", "message": "Domain does not exist."}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, "/verify-records") {
+					gotPath = r.URL.Path
+					if tt.status != 0 {
+						w.WriteHeader(tt.status)
+					}
+					fmt.Fprint(w, tt.response)
+					return
+				}
+				if strings.Contains(tt.record, "statusCode") {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				fmt.Fprint(w, tt.record)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got, err := c.VerifyDomainRecords(tt.domain)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if err == nil && tt.wantErr != "" || err != nil && err.Error() != tt.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if gotPath != tt.wantPath {
+				t.Fatalf("unexpected path %s", gotPath)
+			}
+		})
+	}
+}
+
+func TestClient_VerifyDomainSMTPRecords(t *testing.T) {
+	var gotPaths []string
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPaths = append(gotPaths, r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, "/verify-smtp") {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"statusCode": 400, "error": "Bad Request", "message": "DKIM and DMARC records are not configured."}`)
+			return
+		}
+		fmt.Fprint(w, `{"name": "stark.com", "has_dkim_record": false, "has_return_path_record": true, "has_dmarc_record": false}`)
+	}))
+	defer svr.Close()
+
+	c := NewClient(ClientOptions{
+		ApiUrl: svr.URL,
+	})
+
+	got, err := c.VerifyDomainSMTPRecords("stark.com")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got.Verified || got.Message != "DKIM and DMARC records are not configured." {
+		t.Fatalf("unexpected verification %+v", got)
+	}
+
+	wantFailed := []DnsRecordCheck{
+		{Kind: DnsRecordKindDkim},
+		{Kind: DnsRecordKindDmarc},
+	}
+	if diff := cmp.Diff(wantFailed, got.Failed()); diff != "" {
+		t.Fatalf("values are not the same %s", diff)
+	}
+	if diff := cmp.Diff([]string{"/v1/domains/stark.com/verify-smtp", "/v1/domains/stark.com"}, gotPaths); diff != "" {
+		t.Fatalf("paths are not the same %s", diff)
+	}
+}
+
+func TestVerificationMessage(t *testing.T) {
+	for body, want := range map[string]string{
+		`"Verified."`:              "Verified.",
+		`{"message": "Verified."}`: "Verified.",
+		"Verified.\n":              "Verified.",
+	} {
+		if got := verificationMessage([]byte(body)); got != want {
+			t.Fatalf("unexpected message %q for %q", got, body)
+		}
+	}
+}
+
 // I took this black magic from here (thanks Joe):
 // https://github.com/google/go-cmp/issues/24#issuecomment-317635190
 func equateErrorMessage(x, y error) bool {
@@ -0,0 +1,217 @@
+package forwardemail
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type Email struct {
+	Account     Account       `json:"user"`
+	Domain      Domain        `json:"domain"`
+	Alias       Alias         `json:"alias"`
+	Envelope    EmailEnvelope `json:"envelope"`
+	MessageId   string        `json:"messageId"`
+	Date        time.Time     `json:"date"`
+	Subject     string        `json:"subject"`
+	Status      string        `json:"status"`
+	Accepted    []string      `json:"accepted"`
+	IsRedacted  bool          `json:"is_redacted"`
+	IsBounce    bool          `json:"is_bounce"`
+	IsLocked    bool          `json:"is_locked"`
+	HardBounces []string      `json:"hard_bounces"`
+	SoftBounces []string      `json:"soft_bounces"`
+	Message     string        `json:"message"`
+	Id          string        `json:"id"`
+	Object      string        `json:"object"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	Link        string        `json:"link"`
+}
+
+type EmailEnvelope struct {
+	From string   `json:"from"`
+	To   []string `json:"to"`
+}
+
+type EmailAttachment struct {
+	Filename    string
+	Content     string
+	ContentType string
+	Encoding    string
+	Cid         string
+}
+
+// EmailParameters describes an outbound email. Either Raw is set to a
+// complete RFC 5322 message, or the structured fields are used to compose one.
+type EmailParameters struct {
+	Raw         *string
+	From        *string
+	To          *[]string
+	Cc          *[]string
+	Bcc         *[]string
+	ReplyTo     *string
+	Subject     *string
+	Text        *string
+	Html        *string
+	InReplyTo   *string
+	References  *[]string
+	MessageId   *string
+	Headers     *map[string]string
+	Attachments *[]EmailAttachment
+}
+
+func (c *Client) SendEmail(parameters EmailParameters) (*Email, error) {
+	return c.SendEmailContext(context.Background(), parameters)
+}
+
+// SendEmailContext is like SendEmail but carries ctx with the request.
+func (c *Client) SendEmailContext(ctx context.Context, parameters EmailParameters) (*Email, error) {
+	req, err := c.newRequest(ctx, "POST", "/v1/emails")
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+
+	for k, v := range map[string]*string{
+		"raw":       parameters.Raw,
+		"from":      parameters.From,
+		"replyTo":   parameters.ReplyTo,
+		"subject":   parameters.Subject,
+		"text":      parameters.Text,
+		"html":      parameters.Html,
+		"inReplyTo": parameters.InReplyTo,
+		"messageId": parameters.MessageId,
+	} {
+		if v != nil {
+			params.Add(k, *v)
+		}
+	}
+
+	for k, v := range map[string]*[]string{
+		"to[]":         parameters.To,
+		"cc[]":         parameters.Cc,
+		"bcc[]":        parameters.Bcc,
+		"references[]": parameters.References,
+	} {
+		if v != nil {
+			for _, vv := range *v {
+				params.Add(k, vv)
+			}
+		}
+	}
+
+	if parameters.Headers != nil {
+		for k, v := range *parameters.Headers {
+			params.Add(fmt.Sprintf("headers[%s]", k), v)
+		}
+	}
+
+	if parameters.Attachments != nil {
+		for i, a := range *parameters.Attachments {
+			prefix := "attachments[" + strconv.Itoa(i) + "]"
+			for k, v := range map[string]string{
+				"filename":    a.Filename,
+				"content":     a.Content,
+				"contentType": a.ContentType,
+				"encoding":    a.Encoding,
+				"cid":         a.Cid,
+			} {
+				if v != "" {
+					params.Add(prefix+"["+k+"]", v)
+				}
+			}
+		}
+	}
+
+	setFormBody(req, params)
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item Email
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) ListEmails() ([]Email, error) {
+	return c.ListEmailsContext(context.Background())
+}
+
+// ListEmailsContext is like ListEmails but carries ctx with the request.
+func (c *Client) ListEmailsContext(ctx context.Context) ([]Email, error) {
+	req, err := c.newRequest(ctx, "GET", "/v1/emails")
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []Email
+
+	err = json.Unmarshal(res, &items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (c *Client) GetEmail(id string) (*Email, error) {
+	return c.GetEmailContext(context.Background(), id)
+}
+
+// GetEmailContext is like GetEmail but carries ctx with the request.
+func (c *Client) GetEmailContext(ctx context.Context, id string) (*Email, error) {
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("/v1/emails/%s", id))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item Email
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) DeleteEmail(id string) error {
+	return c.DeleteEmailContext(context.Background(), id)
+}
+
+// DeleteEmailContext is like DeleteEmail but carries ctx with the request.
+func (c *Client) DeleteEmailContext(ctx context.Context, id string) error {
+	req, err := c.newRequest(ctx, "DELETE", fmt.Sprintf("/v1/emails/%s", id))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
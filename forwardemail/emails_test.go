@@ -0,0 +1,329 @@
+package forwardemail
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient_SendEmail(t *testing.T) {
+	tests := []struct {
+		name       string
+		parameters EmailParameters
+		response   string
+		wantForm   url.Values
+		want       *Email
+	}{
+		{
+			name:     "no data",
+			wantForm: url.Values{},
+		},
+		{
+			name: "raw",
+			parameters: EmailParameters{
+				Raw: pointString("From: tony@stark.com\r\nTo: james@rhodes.com\r\nSubject: Hi\r\n\r\nHello"),
+			},
+			response: `{
+				"envelope": {
+				  "from": "tony@stark.com",
+				  "to": ["james@rhodes.com"]
+				},
+				"messageId": "<6531b03e@stark.com>",
+				"subject": "Hi",
+				"status": "queued",
+				"accepted": [],
+				"id": "6531b03e0bde8f333ace5830",
+				"object": "email",
+				"created_at": "2023-10-20T10:12:46.588Z",
+				"updated_at": "2023-10-20T10:12:46.588Z",
+				"link": "https://forwardemail.net/my-account/emails/6531b03e0bde8f333ace5830"
+			}`,
+			wantForm: url.Values{
+				"raw": {"From: tony@stark.com\r\nTo: james@rhodes.com\r\nSubject: Hi\r\n\r\nHello"},
+			},
+			want: &Email{
+				Envelope: EmailEnvelope{
+					From: "tony@stark.com",
+					To:   []string{"james@rhodes.com"},
+				},
+				MessageId: "<6531b03e@stark.com>",
+				Subject:   "Hi",
+				Status:    "queued",
+				Accepted:  []string{},
+				Id:        "6531b03e0bde8f333ace5830",
+				Object:    "email",
+				CreatedAt: parseTime("2023-10-20T10:12:46.588Z"),
+				UpdatedAt: parseTime("2023-10-20T10:12:46.588Z"),
+				Link:      "https://forwardemail.net/my-account/emails/6531b03e0bde8f333ace5830",
+			},
+		},
+		{
+			name: "structured",
+			parameters: EmailParameters{
+				From:    pointString("tony@stark.com"),
+				To:      pointSliceOfStrings([]string{"james@rhodes.com", "pepper@stark.com"}),
+				Cc:      pointSliceOfStrings([]string{"happy@stark.com"}),
+				Bcc:     pointSliceOfStrings([]string{"jarvis@stark.com"}),
+				Subject: pointString("Suit"),
+				Text:    pointString("Suit is ready"),
+				Html:    pointString("<p>Suit is ready</p>"),
+				Headers: &map[string]string{"X-Mark": "42"},
+				Attachments: &[]EmailAttachment{
+					{
+						Filename:    "suit.txt",
+						Content:     "bWFyayA0Mg==",
+						ContentType: "text/plain",
+						Encoding:    "base64",
+					},
+				},
+			},
+			response: `{
+				"envelope": {
+				  "from": "tony@stark.com",
+				  "to": ["james@rhodes.com", "pepper@stark.com", "happy@stark.com", "jarvis@stark.com"]
+				},
+				"subject": "Suit",
+				"status": "queued",
+				"id": "6531b03e0bde8f333ace5831",
+				"object": "email"
+			}`,
+			wantForm: url.Values{
+				"from":                        {"tony@stark.com"},
+				"to[]":                        {"james@rhodes.com", "pepper@stark.com"},
+				"cc[]":                        {"happy@stark.com"},
+				"bcc[]":                       {"jarvis@stark.com"},
+				"subject":                     {"Suit"},
+				"text":                        {"Suit is ready"},
+				"html":                        {"<p>Suit is ready</p>"},
+				"headers[X-Mark]":             {"42"},
+				"attachments[0][filename]":    {"suit.txt"},
+				"attachments[0][content]":     {"bWFyayA0Mg=="},
+				"attachments[0][contentType]": {"text/plain"},
+				"attachments[0][encoding]":    {"base64"},
+			},
+			want: &Email{
+				Envelope: EmailEnvelope{
+					From: "tony@stark.com",
+					To:   []string{"james@rhodes.com", "pepper@stark.com", "happy@stark.com", "jarvis@stark.com"},
+				},
+				Subject: "Suit",
+				Status:  "queued",
+				Id:      "6531b03e0bde8f333ace5831",
+				Object:  "email",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotForm url.Values
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				gotForm = r.PostForm
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got, _ := c.SendEmail(tt.parameters)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantForm, gotForm); diff != "" {
+				t.Fatalf("form values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_ListEmails(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     []Email
+	}{
+		{
+			name: "no data",
+		},
+		{
+			name: "ok",
+			response: `[
+				{
+				  "subject": "Suit",
+				  "status": "delivered",
+				  "accepted": ["james@rhodes.com"],
+				  "id": "6531b03e0bde8f333ace5831",
+				  "object": "email",
+				  "created_at": "2023-10-20T10:12:46.588Z",
+				  "updated_at": "2023-10-20T10:13:01.102Z"
+				},
+				{
+				  "subject": "Armor",
+				  "status": "bounced",
+				  "is_bounce": true,
+				  "hard_bounces": ["obadiah@stane.com"],
+				  "id": "6531b03e0bde8f333ace5832",
+				  "object": "email",
+				  "created_at": "2023-10-21T10:12:46.588Z",
+				  "updated_at": "2023-10-21T10:13:01.102Z"
+				}
+			]`,
+			want: []Email{
+				{
+					Subject:   "Suit",
+					Status:    "delivered",
+					Accepted:  []string{"james@rhodes.com"},
+					Id:        "6531b03e0bde8f333ace5831",
+					Object:    "email",
+					CreatedAt: parseTime("2023-10-20T10:12:46.588Z"),
+					UpdatedAt: parseTime("2023-10-20T10:13:01.102Z"),
+				},
+				{
+					Subject:     "Armor",
+					Status:      "bounced",
+					IsBounce:    true,
+					HardBounces: []string{"obadiah@stane.com"},
+					Id:          "6531b03e0bde8f333ace5832",
+					Object:      "email",
+					CreatedAt:   parseTime("2023-10-21T10:12:46.588Z"),
+					UpdatedAt:   parseTime("2023-10-21T10:13:01.102Z"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got, _ := c.ListEmails()
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_GetEmail(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		response string
+		want     *Email
+	}{
+		{
+			name: "no data",
+		},
+		{
+			name: "ok",
+			id:   "6531b03e0bde8f333ace5831",
+			response: `{
+				"envelope": {
+				  "from": "tony@stark.com",
+				  "to": ["james@rhodes.com"]
+				},
+				"subject": "Suit",
+				"status": "delivered",
+				"message": "From: tony@stark.com\r\nSubject: Suit\r\n\r\nSuit is ready",
+				"id": "6531b03e0bde8f333ace5831",
+				"object": "email"
+			}`,
+			want: &Email{
+				Envelope: EmailEnvelope{
+					From: "tony@stark.com",
+					To:   []string{"james@rhodes.com"},
+				},
+				Subject: "Suit",
+				Status:  "delivered",
+				Message: "From: tony@stark.com\r\nSubject: Suit\r\n\r\nSuit is ready",
+				Id:      "6531b03e0bde8f333ace5831",
+				Object:  "email",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got, _ := c.GetEmail(tt.id)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteEmail(t *testing.T) {
+	type response struct {
+		code int
+		body string
+	}
+
+	tests := []struct {
+		name string
+		id   string
+		resp response
+		want error
+	}{
+		{
+			name: "ok",
+			resp: response{
+				code: http.StatusNoContent,
+			},
+		},
+		{
+			name: "not ok",
+			resp: response{
+				code: http.StatusInternalServerError,
+				body: "oh no",
+			},
+			want: &APIError{StatusCode: 500, Body: []byte("oh no")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.resp.code)
+				fmt.Fprintf(w, tt.resp.body)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got := c.DeleteEmail(tt.id)
+			if diff := cmp.Diff(tt.want, got, cmp.Comparer(equateErrorMessage)); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func pointString(s string) *string {
+	return &s
+}
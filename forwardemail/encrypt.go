@@ -0,0 +1,42 @@
+package forwardemail
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// EncryptTXTRecord encrypts a plaintext TXT record such as
+// "forward-email=tony:james@rhodes.com" and returns the value to publish.
+func (c *Client) EncryptTXTRecord(input string) (string, error) {
+	return c.EncryptTXTRecordContext(context.Background(), input)
+}
+
+// EncryptTXTRecordContext is like EncryptTXTRecord but carries ctx with the
+// request.
+func (c *Client) EncryptTXTRecordContext(ctx context.Context, input string) (string, error) {
+	req, err := c.newRequest(ctx, "POST", "/v1/encrypt")
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Add("input", input)
+
+	setFormBody(req, params)
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return "", err
+	}
+
+	// The record comes back as plain text, but tolerate a JSON string too.
+	var item string
+
+	if json.Unmarshal(res, &item) != nil {
+		item = string(res)
+	}
+
+	return strings.TrimSpace(item), nil
+}
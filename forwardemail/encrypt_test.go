@@ -0,0 +1,83 @@
+package forwardemail
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient_EncryptTXTRecord(t *testing.T) {
+	type response struct {
+		code int
+		body string
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		resp      response
+		wantInput string
+		want      string
+		wantErr   error
+	}{
+		{
+			name:  "plain text",
+			input: "forward-email=tony:james@rhodes.com",
+			resp: response{
+				code: http.StatusOK,
+				body: "forward-email=aGVsbG8gd29ybGQ6ZW5jcnlwdGVk\n",
+			},
+			wantInput: "forward-email=tony:james@rhodes.com",
+			want:      "forward-email=aGVsbG8gd29ybGQ6ZW5jcnlwdGVk",
+		},
+		{
+			name:  "json string",
+			input: "forward-email=tony:james@rhodes.com",
+			resp: response{
+				code: http.StatusOK,
+				body: `"forward-email=aGVsbG8gd29ybGQ6ZW5jcnlwdGVk"`,
+			},
+			wantInput: "forward-email=tony:james@rhodes.com",
+			want:      "forward-email=aGVsbG8gd29ybGQ6ZW5jcnlwdGVk",
+		},
+		{
+			name: "not ok",
+			resp: response{
+				code: http.StatusBadRequest,
+				body: "invalid input",
+			},
+			wantErr: &APIError{StatusCode: 400, Body: []byte("invalid input")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotInput string
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotInput = r.PostFormValue("input")
+				w.WriteHeader(tt.resp.code)
+				fmt.Fprintf(w, tt.resp.body)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got, err := c.EncryptTXTRecord(tt.input)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantErr, err, cmp.Comparer(equateErrorMessage)); diff != "" {
+				t.Fatalf("errors are not the same %s", diff)
+			}
+			if gotInput != tt.wantInput {
+				t.Fatalf("unexpected input %q", gotInput)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package forwardemail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors matched by an *APIError with errors.Is.
+var (
+	ErrNotFound     = errors.New("forwardemail: not found")
+	ErrUnauthorized = errors.New("forwardemail: unauthorized")
+	ErrForbidden    = errors.New("forwardemail: forbidden")
+	ErrRateLimited  = errors.New("forwardemail: rate limited")
+	ErrValidation   = errors.New("forwardemail: validation failed")
+)
+
+// ErrNoAliasCredentials is returned before sending a request that
+// authenticates as an alias when AliasUsername or AliasPassword is empty.
+var ErrNoAliasCredentials = errors.New("forwardemail: alias credentials are not set")
+
+// APIError is returned for every response with an unexpected status code.
+type APIError struct {
+	StatusCode int
+	// Reason and Message are the "error" and "message" fields of the JSON
+	// body, such as "Not Found" and "Alias does not exist.", when present.
+	Reason  string
+	Message string
+
+	Method    string
+	Path      string
+	RequestId string
+
+	Body []byte
+}
+
+func newAPIError(req *http.Request, res *http.Response, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: res.StatusCode,
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		RequestId:  res.Header.Get("X-Request-Id"),
+		Body:       body,
+	}
+
+	var fields struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+
+	if json.Unmarshal(body, &fields) == nil {
+		e.Reason, e.Message = fields.Error, fields.Message
+	}
+
+	return e
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("status: %d, body: %s", e.StatusCode, e.Body)
+}
+
+// Is maps the status code to one of the sentinel errors.
+func (e *APIError) Is(target error) bool {
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusUnauthorized:
+		return target == ErrUnauthorized
+	case http.StatusForbidden:
+		return target == ErrForbidden
+	case http.StatusTooManyRequests:
+		return target == ErrRateLimited
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return target == ErrValidation
+	}
+
+	return false
+}
@@ -0,0 +1,77 @@
+package forwardemail
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAPIError(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "8f2b1c")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `{"statusCode": 404, "error": "Not Found", "message": "Alias does not exist."}`)
+	}))
+	defer svr.Close()
+
+	c := NewClient(ClientOptions{
+		ApiUrl: svr.URL,
+	})
+
+	_, err := c.GetAlias("stark.com", "tony")
+
+	var got *APIError
+	if !errors.As(err, &got) {
+		t.Fatalf("unexpected error type %T", err)
+	}
+
+	want := &APIError{
+		StatusCode: 404,
+		Reason:     "Not Found",
+		Message:    "Alias does not exist.",
+		Method:     "GET",
+		Path:       "/v1/domains/stark.com/aliases/tony",
+		RequestId:  "8f2b1c",
+		Body:       []byte(`{"statusCode": 404, "error": "Not Found", "message": "Alias does not exist."}`),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("values are not the same %s", diff)
+	}
+
+	if msg := err.Error(); msg != `status: 404, body: {"statusCode": 404, "error": "Not Found", "message": "Alias does not exist."}` {
+		t.Fatalf("unexpected message %s", msg)
+	}
+}
+
+func TestAPIError_Is(t *testing.T) {
+	sentinels := []error{ErrNotFound, ErrUnauthorized, ErrForbidden, ErrRateLimited, ErrValidation}
+
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{status: 400, want: ErrValidation},
+		{status: 401, want: ErrUnauthorized},
+		{status: 403, want: ErrForbidden},
+		{status: 404, want: ErrNotFound},
+		{status: 422, want: ErrValidation},
+		{status: 429, want: ErrRateLimited},
+		{status: 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			err := fmt.Errorf("wrapped: %w", &APIError{StatusCode: tt.status})
+
+			for _, sentinel := range sentinels {
+				if got := errors.Is(err, sentinel); got != (sentinel == tt.want) {
+					t.Fatalf("errors.Is(%v) = %v", sentinel, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package forwardemail
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	FolderSpecialUseArchive = `\Archive`
+	FolderSpecialUseDrafts  = `\Drafts`
+	FolderSpecialUseJunk    = `\Junk`
+	FolderSpecialUseSent    = `\Sent`
+	FolderSpecialUseTrash   = `\Trash`
+)
+
+type Folder struct {
+	Path        string    `json:"path"`
+	Name        string    `json:"name"`
+	SpecialUse  string    `json:"special_use"`
+	Subscribed  bool      `json:"subscribed"`
+	UidValidity int64     `json:"uid_validity"`
+	UidNext     int64     `json:"uid_next"`
+	Id          string    `json:"id"`
+	Object      string    `json:"object"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// FolderParameters creates a folder when passed to CreateFolder, and renames
+// or (un)subscribes one when passed to UpdateFolder.
+type FolderParameters struct {
+	Path       *string
+	SpecialUse *string
+	Subscribed *bool
+}
+
+func (c *Client) GetFolders() ([]Folder, error) {
+	return c.GetFoldersContext(context.Background())
+}
+
+// GetFoldersContext is like GetFolders but carries ctx with the request.
+func (c *Client) GetFoldersContext(ctx context.Context) ([]Folder, error) {
+	req, err := c.newAliasRequest(ctx, "GET", "/v1/folders")
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []Folder
+
+	err = json.Unmarshal(res, &items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (c *Client) GetFolder(id string) (*Folder, error) {
+	return c.GetFolderContext(context.Background(), id)
+}
+
+// GetFolderContext is like GetFolder but carries ctx with the request.
+func (c *Client) GetFolderContext(ctx context.Context, id string) (*Folder, error) {
+	req, err := c.newAliasRequest(ctx, "GET", fmt.Sprintf("/v1/folders/%s", id))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item Folder
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) CreateFolder(parameters FolderParameters) (*Folder, error) {
+	return c.CreateFolderContext(context.Background(), parameters)
+}
+
+// CreateFolderContext is like CreateFolder but carries ctx with the request.
+func (c *Client) CreateFolderContext(ctx context.Context, parameters FolderParameters) (*Folder, error) {
+	req, err := c.newAliasRequest(ctx, "POST", "/v1/folders")
+	if err != nil {
+		return nil, err
+	}
+
+	setFormBody(req, parameters.encode())
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item Folder
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) UpdateFolder(id string, parameters FolderParameters) (*Folder, error) {
+	return c.UpdateFolderContext(context.Background(), id, parameters)
+}
+
+// UpdateFolderContext is like UpdateFolder but carries ctx with the request.
+func (c *Client) UpdateFolderContext(ctx context.Context, id string, parameters FolderParameters) (*Folder, error) {
+	req, err := c.newAliasRequest(ctx, "PUT", fmt.Sprintf("/v1/folders/%s", id))
+	if err != nil {
+		return nil, err
+	}
+
+	setFormBody(req, parameters.encode())
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item Folder
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) DeleteFolder(id string) error {
+	return c.DeleteFolderContext(context.Background(), id)
+}
+
+// DeleteFolderContext is like DeleteFolder but carries ctx with the request.
+func (c *Client) DeleteFolderContext(ctx context.Context, id string) error {
+	req, err := c.newAliasRequest(ctx, "DELETE", fmt.Sprintf("/v1/folders/%s", id))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p FolderParameters) encode() url.Values {
+	params := url.Values{}
+
+	for k, v := range map[string]*string{
+		"path":        p.Path,
+		"special_use": p.SpecialUse,
+	} {
+		if v != nil {
+			params.Add(k, *v)
+		}
+	}
+
+	if p.Subscribed != nil {
+		params.Add("subscribed", strconv.FormatBool(*p.Subscribed))
+	}
+
+	return params
+}
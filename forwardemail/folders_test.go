@@ -0,0 +1,342 @@
+package forwardemail
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient_GetFolders(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     []Folder
+	}{
+		{
+			name: "no data",
+		},
+		{
+			name: "ok",
+			response: `[
+				{
+				  "path": "INBOX",
+				  "name": "INBOX",
+				  "subscribed": true,
+				  "uid_validity": 1697796766,
+				  "uid_next": 43,
+				  "id": "6531b03e0bde8f333ace7001",
+				  "object": "folder",
+				  "created_at": "2023-10-20T10:12:46.588Z",
+				  "updated_at": "2023-10-20T10:12:46.588Z"
+				},
+				{
+				  "path": "Sent Mail",
+				  "name": "Sent Mail",
+				  "special_use": "\\Sent",
+				  "subscribed": true,
+				  "id": "6531b03e0bde8f333ace7002",
+				  "object": "folder"
+				}
+			]`,
+			want: []Folder{
+				{
+					Path:        "INBOX",
+					Name:        "INBOX",
+					Subscribed:  true,
+					UidValidity: 1697796766,
+					UidNext:     43,
+					Id:          "6531b03e0bde8f333ace7001",
+					Object:      "folder",
+					CreatedAt:   parseTime("2023-10-20T10:12:46.588Z"),
+					UpdatedAt:   parseTime("2023-10-20T10:12:46.588Z"),
+				},
+				{
+					Path:       "Sent Mail",
+					Name:       "Sent Mail",
+					SpecialUse: FolderSpecialUseSent,
+					Subscribed: true,
+					Id:         "6531b03e0bde8f333ace7002",
+					Object:     "folder",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUser, gotPassword string
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotUser, gotPassword, _ = r.BasicAuth()
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				ApiKey:        "4e4d6c332b6fe62a63afe56171fd3725",
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			})
+
+			got, _ := c.GetFolders()
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if gotUser != "tony@stark.com" || gotPassword != "i-am-iron-man" {
+				t.Fatalf("unexpected credentials %s:%s", gotUser, gotPassword)
+			}
+		})
+	}
+}
+
+func TestClient_GetFolder(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		response string
+		want     *Folder
+	}{
+		{
+			name: "no data",
+		},
+		{
+			name: "ok",
+			id:   "6531b03e0bde8f333ace7003",
+			response: `{
+				"path": "Trash",
+				"name": "Trash",
+				"special_use": "\\Trash",
+				"id": "6531b03e0bde8f333ace7003",
+				"object": "folder"
+			}`,
+			want: &Folder{
+				Path:       "Trash",
+				Name:       "Trash",
+				SpecialUse: FolderSpecialUseTrash,
+				Id:         "6531b03e0bde8f333ace7003",
+				Object:     "folder",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			})
+
+			got, _ := c.GetFolder(tt.id)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_CreateFolder(t *testing.T) {
+	tests := []struct {
+		name       string
+		parameters FolderParameters
+		response   string
+		wantForm   url.Values
+		want       *Folder
+	}{
+		{
+			name:     "no data",
+			wantForm: url.Values{},
+		},
+		{
+			name: "ok",
+			parameters: FolderParameters{
+				Path:       pointString("Archive/2023"),
+				SpecialUse: pointString(FolderSpecialUseArchive),
+				Subscribed: pointBool(true),
+			},
+			response: `{
+				"path": "Archive/2023",
+				"name": "2023",
+				"special_use": "\\Archive",
+				"subscribed": true,
+				"id": "6531b03e0bde8f333ace7004",
+				"object": "folder"
+			}`,
+			wantForm: url.Values{
+				"path":        {"Archive/2023"},
+				"special_use": {`\Archive`},
+				"subscribed":  {"true"},
+			},
+			want: &Folder{
+				Path:       "Archive/2023",
+				Name:       "2023",
+				SpecialUse: FolderSpecialUseArchive,
+				Subscribed: true,
+				Id:         "6531b03e0bde8f333ace7004",
+				Object:     "folder",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotForm url.Values
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				gotForm = r.PostForm
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			})
+
+			got, _ := c.CreateFolder(tt.parameters)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantForm, gotForm); diff != "" {
+				t.Fatalf("form values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_UpdateFolder(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		parameters FolderParameters
+		response   string
+		wantPath   string
+		wantForm   url.Values
+		want       *Folder
+	}{
+		{
+			name:     "no data",
+			wantPath: "/v1/folders/",
+			wantForm: url.Values{},
+		},
+		{
+			name: "rename and unsubscribe",
+			id:   "6531b03e0bde8f333ace7004",
+			parameters: FolderParameters{
+				Path:       pointString("Archive/Old/2023"),
+				Subscribed: pointBool(false),
+			},
+			response: `{
+				"path": "Archive/Old/2023",
+				"name": "2023",
+				"subscribed": false,
+				"id": "6531b03e0bde8f333ace7004",
+				"object": "folder"
+			}`,
+			wantPath: "/v1/folders/6531b03e0bde8f333ace7004",
+			wantForm: url.Values{
+				"path":       {"Archive/Old/2023"},
+				"subscribed": {"false"},
+			},
+			want: &Folder{
+				Path:   "Archive/Old/2023",
+				Name:   "2023",
+				Id:     "6531b03e0bde8f333ace7004",
+				Object: "folder",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			var gotForm url.Values
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				gotPath, gotForm = r.URL.Path, r.PostForm
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			})
+
+			got, _ := c.UpdateFolder(tt.id, tt.parameters)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if gotPath != tt.wantPath {
+				t.Fatalf("unexpected path %s", gotPath)
+			}
+			if diff := cmp.Diff(tt.wantForm, gotForm); diff != "" {
+				t.Fatalf("form values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteFolder(t *testing.T) {
+	type response struct {
+		code int
+		body string
+	}
+
+	tests := []struct {
+		name string
+		id   string
+		resp response
+		want error
+	}{
+		{
+			name: "ok",
+			resp: response{
+				code: http.StatusNoContent,
+			},
+		},
+		{
+			name: "not ok",
+			resp: response{
+				code: http.StatusInternalServerError,
+				body: "oh no",
+			},
+			want: &APIError{StatusCode: 500, Body: []byte("oh no")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.resp.code)
+				fmt.Fprintf(w, tt.resp.body)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			})
+
+			got := c.DeleteFolder(tt.id)
+			if diff := cmp.Diff(tt.want, got, cmp.Comparer(equateErrorMessage)); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,642 @@
+package forwardemail
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	icalProdId     = "-//abagayev//go-forwardemail//EN"
+	icalDateFormat = "20060102"
+	icalTimeFormat = "20060102T150405"
+)
+
+// ICalFloating is the location of floating times, which have no time zone
+// and happen at the same wall clock time wherever the attendee is. Parsed
+// times without TZID or "Z" use it, and it is written back the same way.
+var ICalFloating = time.FixedZone("Floating", 0)
+
+var icalDurationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// ICalendar renders the event as a standalone RFC 5545 VCALENDAR object,
+// including VTIMEZONE definitions for the zones its dates are in.
+func (e CalendarEvent) ICalendar() string {
+	var b strings.Builder
+
+	_ = WriteICalendar(&b, []CalendarEvent{e})
+
+	return b.String()
+}
+
+// WriteICalendar writes the events as a single .ics stream. Dates keep their
+// time.Location as TZID, UTC dates are written with a "Z" suffix and
+// ICalFloating dates without either. Properties and alarms of the event in
+// Ical that have no CalendarEvent field, such as ATTENDEE, RDATE or VALARM,
+// are written back unchanged.
+func WriteICalendar(w io.Writer, events []CalendarEvent) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + icalProdId,
+		"CALSCALE:GREGORIAN",
+	}
+
+	extras := make([]icalExtras, len(events))
+	seen := map[string]bool{}
+	for i, e := range events {
+		extras[i] = e.icalExtras()
+
+		if !e.AllDay {
+			for _, t := range append([]time.Time{e.Start, e.End, e.RecurrenceId}, e.ExDates...) {
+				loc := t.Location()
+				if t.IsZero() || !isZonedLocation(loc) || seen[loc.String()] {
+					continue
+				}
+				seen[loc.String()] = true
+				lines = append(lines, vtimezone(loc, e.Start.Year())...)
+			}
+		}
+
+		tzids := make([]string, 0, len(extras[i].timezones))
+		for tzid := range extras[i].timezones {
+			tzids = append(tzids, tzid)
+		}
+		sort.Strings(tzids)
+		for _, tzid := range tzids {
+			if !seen[tzid] {
+				seen[tzid] = true
+				lines = append(lines, extras[i].timezones[tzid]...)
+			}
+		}
+	}
+
+	for i, e := range events {
+		lines = append(lines, e.vevent(extras[i])...)
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, foldContentLine(line)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e CalendarEvent) vevent(extras icalExtras) []string {
+	uid := e.Uid
+	if uid == "" {
+		uid = e.Id
+	}
+	if uid == "" {
+		b := make([]byte, 16)
+		_, _ = rand.Read(b)
+		uid = hex.EncodeToString(b)
+	}
+
+	stamp := e.UpdatedAt
+	if stamp.IsZero() {
+		stamp = e.CreatedAt
+	}
+	if stamp.IsZero() && extras.stamp == "" {
+		stamp = time.Now()
+	}
+	if !stamp.IsZero() {
+		extras.stamp = "DTSTAMP:" + stamp.UTC().Format(icalTimeFormat+"Z")
+	}
+
+	lines := []string{
+		"BEGIN:VEVENT",
+		"UID:" + escapeText(uid),
+		extras.stamp,
+		formatICalTime("DTSTART", e.Start, e.AllDay),
+	}
+
+	if !e.RecurrenceId.IsZero() {
+		lines = append(lines, formatICalTime("RECURRENCE-ID", e.RecurrenceId, e.AllDay))
+	}
+	if !e.End.IsZero() {
+		lines = append(lines, formatICalTime("DTEND", e.End, e.AllDay))
+	}
+	if e.Recurrence != "" {
+		lines = append(lines, "RRULE:"+e.Recurrence)
+	}
+	for _, t := range e.ExDates {
+		lines = append(lines, formatICalTime("EXDATE", t, e.AllDay))
+	}
+
+	for _, p := range []struct{ name, value string }{
+		{"SUMMARY", e.Summary},
+		{"DESCRIPTION", e.Description},
+		{"LOCATION", e.Location},
+	} {
+		if p.value != "" {
+			lines = append(lines, p.name+":"+escapeText(p.value))
+		}
+	}
+
+	lines = append(lines, extras.properties...)
+	lines = append(lines, extras.components...)
+
+	return append(lines, "END:VEVENT")
+}
+
+// icalExtras is what WriteICalendar keeps from the Ical of an event.
+type icalExtras struct {
+	// stamp is the original DTSTAMP line.
+	stamp string
+	// properties and components are the lines of the properties that are not
+	// rendered from the CalendarEvent fields and of nested components such
+	// as VALARM.
+	properties []string
+	components []string
+	// timezones are the VTIMEZONE definitions in Ical by TZID.
+	timezones map[string][]string
+}
+
+// icalExtras reads the VEVENT in Ical with the same UID and RECURRENCE-ID as
+// the event. Nothing is kept when there is none, such as when the event was
+// built by hand.
+func (e CalendarEvent) icalExtras() icalExtras {
+	lines, err := readContentLines(strings.NewReader(e.Ical))
+	if err != nil {
+		return icalExtras{}
+	}
+
+	var stack []string
+	var current, found icalExtras
+	var uid string
+	var recurrenceId time.Time
+	var tzid string
+	var timezone []string
+	timezones := map[string][]string{}
+	offsets := map[string]int{}
+
+	for _, l := range lines {
+		name, params, value, ok := parseContentLine(l.text)
+		if !ok {
+			return icalExtras{}
+		}
+
+		if name == "BEGIN" {
+			stack = append(stack, strings.ToUpper(value))
+			if len(stack) == 2 {
+				current, uid, recurrenceId, tzid, timezone = icalExtras{}, "", time.Time{}, "", nil
+			}
+		}
+
+		inside := ""
+		if len(stack) >= 2 {
+			inside = stack[1]
+		}
+
+		switch {
+		case inside == "VTIMEZONE":
+			timezone = append(timezone, l.text)
+			switch {
+			case name == "TZID":
+				tzid = value
+			case name == "TZOFFSETTO" && len(stack) == 3 && stack[2] == "STANDARD":
+				if offset, ok := parseICalOffset(value); ok {
+					offsets[tzid] = offset
+				}
+			}
+		case inside == "VEVENT" && len(stack) > 2:
+			current.components = append(current.components, l.text)
+		case inside == "VEVENT":
+			switch name {
+			case "UID":
+				uid = unescapeText(value)
+			case "RECURRENCE-ID":
+				recurrenceId, _, _ = parseICalTime(value, params, offsets)
+			case "DTSTAMP":
+				current.stamp = l.text
+			case "BEGIN", "END", "DTSTART", "DTEND", "DURATION", "RRULE", "EXDATE", "SUMMARY", "DESCRIPTION", "LOCATION":
+			default:
+				current.properties = append(current.properties, l.text)
+			}
+		}
+
+		if name == "END" && len(stack) > 0 {
+			if len(stack) == 2 {
+				switch inside {
+				case "VTIMEZONE":
+					timezones[tzid] = timezone
+				case "VEVENT":
+					if (e.Uid == "" || uid == e.Uid) && recurrenceId.Equal(e.RecurrenceId) {
+						found = current
+					}
+				}
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	for _, line := range found.properties {
+		_, params, _, _ := parseContentLine(line)
+		for _, tz := range params["TZID"] {
+			if timezones[tz] != nil {
+				if found.timezones == nil {
+					found.timezones = map[string][]string{}
+				}
+				found.timezones[tz] = timezones[tz]
+			}
+		}
+	}
+
+	return found
+}
+
+func formatICalTime(name string, t time.Time, allDay bool) string {
+	if allDay {
+		return name + ";VALUE=DATE:" + t.Format(icalDateFormat)
+	}
+
+	if t.Location() == ICalFloating {
+		return name + ":" + t.Format(icalTimeFormat)
+	}
+
+	if loc := t.Location(); isZonedLocation(loc) {
+		tzid := loc.String()
+		if strings.ContainsAny(tzid, ":;,") {
+			tzid = `"` + tzid + `"`
+		}
+		return name + ";TZID=" + tzid + ":" + t.Format(icalTimeFormat)
+	}
+
+	return name + ":" + t.UTC().Format(icalTimeFormat+"Z")
+}
+
+func isZonedLocation(loc *time.Location) bool {
+	return loc != time.UTC && loc != time.Local && loc != ICalFloating && loc.String() != "UTC"
+}
+
+// vtimezone describes loc around the given year. Zones with daylight saving
+// time get yearly rules derived from the transitions of that year.
+func vtimezone(loc *time.Location, year int) []string {
+	lines := []string{"BEGIN:VTIMEZONE", "TZID:" + loc.String()}
+
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	middle := time.Date(year, time.July, 1, 0, 0, 0, 0, loc)
+	end := time.Date(year+1, time.January, 1, 0, 0, 0, 0, loc)
+
+	startName, startOffset := start.Zone()
+	_, middleOffset := middle.Zone()
+
+	if startOffset == middleOffset {
+		lines = append(lines,
+			"BEGIN:STANDARD",
+			"DTSTART:19700101T000000",
+			"TZOFFSETFROM:"+formatICalOffset(startOffset),
+			"TZOFFSETTO:"+formatICalOffset(startOffset),
+			"TZNAME:"+startName,
+			"END:STANDARD",
+		)
+
+		return append(lines, "END:VTIMEZONE")
+	}
+
+	daylight := startOffset
+	if middleOffset > daylight {
+		daylight = middleOffset
+	}
+
+	for _, t := range []time.Time{findZoneTransition(start, middle), findZoneTransition(middle, end)} {
+		_, from := t.Add(-time.Second).Zone()
+		name, to := t.Zone()
+
+		kind := "STANDARD"
+		if to == daylight {
+			kind = "DAYLIGHT"
+		}
+
+		onset := t.In(time.FixedZone("", from))
+		week := (onset.Day()-1)/7 + 1
+		if onset.AddDate(0, 0, 7).Month() != onset.Month() {
+			week = -1
+		}
+
+		lines = append(lines,
+			"BEGIN:"+kind,
+			"DTSTART:"+onset.Format(icalTimeFormat),
+			"RRULE:FREQ=YEARLY;BYMONTH="+strconv.Itoa(int(onset.Month()))+";BYDAY="+strconv.Itoa(week)+strings.ToUpper(onset.Weekday().String()[:2]),
+			"TZOFFSETFROM:"+formatICalOffset(from),
+			"TZOFFSETTO:"+formatICalOffset(to),
+			"TZNAME:"+name,
+			"END:"+kind,
+		)
+	}
+
+	return append(lines, "END:VTIMEZONE")
+}
+
+// findZoneTransition returns the first second in (from, to] at which the UTC
+// offset differs from the one at from.
+func findZoneTransition(from, to time.Time) time.Time {
+	_, offset := from.Zone()
+
+	for to.Sub(from) > time.Second {
+		middle := from.Add(to.Sub(from) / 2).Truncate(time.Second)
+		if _, o := middle.Zone(); o == offset {
+			from = middle
+		} else {
+			to = middle
+		}
+	}
+
+	return to
+}
+
+func formatICalOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+
+	return fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset%3600/60)
+}
+
+func parseICalOffset(s string) (int, bool) {
+	if len(s) != 5 && len(s) != 7 {
+		return 0, false
+	}
+
+	sign := 1
+	switch s[0] {
+	case '-':
+		sign = -1
+	case '+':
+	default:
+		return 0, false
+	}
+
+	offset := 0
+	for i, unit := range []int{3600, 60, 1} {
+		if 1+2*i+2 > len(s) {
+			break
+		}
+		n, err := strconv.Atoi(s[1+2*i : 1+2*i+2])
+		if err != nil {
+			return 0, false
+		}
+		offset += n * unit
+	}
+
+	return sign * offset, true
+}
+
+type icalProperty struct {
+	line   int
+	name   string
+	params map[string][]string
+	value  string
+}
+
+type icalComponent struct {
+	raw        []string
+	properties []icalProperty
+	tzids      map[string]bool
+}
+
+// ParseICalendar reads every VEVENT from an RFC 5545 stream. Recurrence rules
+// are kept verbatim and dates keep their TZID as time.Location. Each event's
+// Ical holds a standalone VCALENDAR with the event and its VTIMEZONEs.
+func ParseICalendar(r io.Reader) ([]CalendarEvent, error) {
+	lines, err := readContentLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var stack []string
+	var events []*icalComponent
+	var event *icalComponent
+	timezones := map[string][]string{}
+	offsets := map[string]int{}
+	var timezone []string
+	var tzid string
+
+	for _, l := range lines {
+		name, params, value, ok := parseContentLine(l.text)
+		if !ok {
+			return nil, fmt.Errorf("icalendar: line %d: invalid content line %q", l.number, l.text)
+		}
+
+		switch name {
+		case "BEGIN":
+			component := strings.ToUpper(value)
+			if len(stack) == 0 && component != "VCALENDAR" {
+				return nil, fmt.Errorf("icalendar: line %d: unexpected BEGIN:%s outside of VCALENDAR", l.number, value)
+			}
+			stack = append(stack, component)
+			switch {
+			case component == "VEVENT" && len(stack) == 2:
+				event = &icalComponent{tzids: map[string]bool{}}
+			case component == "VTIMEZONE" && len(stack) == 2:
+				timezone, tzid = nil, ""
+			}
+		case "END":
+			component := strings.ToUpper(value)
+			if len(stack) == 0 || stack[len(stack)-1] != component {
+				return nil, fmt.Errorf("icalendar: line %d: unexpected END:%s", l.number, value)
+			}
+			stack = stack[:len(stack)-1]
+			if len(stack) == 1 {
+				switch component {
+				case "VEVENT":
+					event.raw = append(event.raw, l.text)
+					events = append(events, event)
+					event = nil
+					continue
+				case "VTIMEZONE":
+					timezones[tzid] = append(timezone, l.text)
+					timezone = nil
+					continue
+				}
+			}
+		}
+
+		inside := ""
+		if len(stack) >= 2 {
+			inside = stack[1]
+		}
+
+		switch {
+		case inside == "VEVENT":
+			event.raw = append(event.raw, l.text)
+			if len(stack) == 2 {
+				event.properties = append(event.properties, icalProperty{line: l.number, name: name, params: params, value: value})
+				for _, tz := range params["TZID"] {
+					event.tzids[tz] = true
+				}
+			}
+		case inside == "VTIMEZONE":
+			timezone = append(timezone, l.text)
+			if name == "TZID" {
+				tzid = value
+			}
+			// Prefer the standard offset as the fallback for zones that
+			// the local tz database does not know.
+			if name == "TZOFFSETTO" && len(stack) == 3 {
+				if offset, ok := parseICalOffset(value); ok {
+					if _, seen := offsets[tzid]; !seen || stack[2] == "STANDARD" {
+						offsets[tzid] = offset
+					}
+				}
+			}
+		}
+	}
+
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("icalendar: missing END:%s", stack[len(stack)-1])
+	}
+
+	items := make([]CalendarEvent, 0, len(events))
+	for _, component := range events {
+		item, err := component.event(offsets)
+		if err != nil {
+			return nil, err
+		}
+
+		raw := []string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:" + icalProdId}
+		tzids := make([]string, 0, len(component.tzids))
+		for tz := range component.tzids {
+			tzids = append(tzids, tz)
+		}
+		sort.Strings(tzids)
+		for _, tz := range tzids {
+			raw = append(raw, timezones[tz]...)
+		}
+		raw = append(raw, component.raw...)
+		raw = append(raw, "END:VCALENDAR")
+
+		var b strings.Builder
+		for _, line := range raw {
+			b.WriteString(foldContentLine(line))
+		}
+		item.Ical = b.String()
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (c *icalComponent) event(offsets map[string]int) (CalendarEvent, error) {
+	var e CalendarEvent
+	var duration *time.Duration
+
+	for _, p := range c.properties {
+		var err error
+
+		switch p.name {
+		case "UID":
+			e.Uid = unescapeText(p.value)
+		case "SUMMARY":
+			e.Summary = unescapeText(p.value)
+		case "DESCRIPTION":
+			e.Description = unescapeText(p.value)
+		case "LOCATION":
+			e.Location = unescapeText(p.value)
+		case "RRULE":
+			if e.Recurrence == "" {
+				e.Recurrence = p.value
+			}
+		case "DTSTART":
+			e.Start, e.AllDay, err = parseICalTime(p.value, p.params, offsets)
+		case "DTEND":
+			e.End, _, err = parseICalTime(p.value, p.params, offsets)
+		case "DURATION":
+			var d time.Duration
+			d, err = parseICalDuration(p.value)
+			duration = &d
+		case "RECURRENCE-ID":
+			e.RecurrenceId, _, err = parseICalTime(p.value, p.params, offsets)
+		case "EXDATE":
+			for _, v := range strings.Split(p.value, ",") {
+				var t time.Time
+				t, _, err = parseICalTime(v, p.params, offsets)
+				if err != nil {
+					break
+				}
+				e.ExDates = append(e.ExDates, t)
+			}
+		}
+
+		if err != nil {
+			return CalendarEvent{}, fmt.Errorf("icalendar: line %d: %w", p.line, err)
+		}
+	}
+
+	if e.End.IsZero() && duration != nil {
+		e.End = e.Start.Add(*duration)
+	}
+
+	return e, nil
+}
+
+func parseICalTime(value string, params map[string][]string, offsets map[string]int) (time.Time, bool, error) {
+	if len(params["VALUE"]) > 0 && strings.EqualFold(params["VALUE"][0], "DATE") || len(value) == len(icalDateFormat) {
+		t, err := time.Parse(icalDateFormat, value)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(icalTimeFormat+"Z", value)
+		return t, false, err
+	}
+
+	loc := ICalFloating
+	if len(params["TZID"]) > 0 {
+		tzid := params["TZID"][0]
+
+		l, err := time.LoadLocation(strings.TrimPrefix(tzid, "/"))
+		switch {
+		case err == nil:
+			loc = l
+		default:
+			offset, ok := offsets[tzid]
+			if !ok {
+				return time.Time{}, false, fmt.Errorf("unknown time zone %q", tzid)
+			}
+			loc = time.FixedZone(tzid, offset)
+		}
+	}
+
+	t, err := time.ParseInLocation(icalTimeFormat, value, loc)
+
+	return t, false, err
+}
+
+func parseICalDuration(value string) (time.Duration, error) {
+	m := icalDurationPattern.FindStringSubmatch(value)
+	if m == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	var d time.Duration
+	for i, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if m[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+2])
+		if err != nil {
+			return 0, err
+		}
+		d += time.Duration(n) * unit
+	}
+
+	if m[1] == "-" {
+		d = -d
+	}
+
+	return d, nil
+}
@@ -0,0 +1,490 @@
+package forwardemail
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParseICalendar(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+	pacific := time.FixedZone("Pacific Standard Time", -8*3600)
+
+	tests := []struct {
+		name    string
+		ics     string
+		want    []CalendarEvent
+		wantErr string
+	}{
+		{
+			name: "no data",
+			want: []CalendarEvent{},
+		},
+		{
+			name: "recurring event with time zone",
+			ics: "BEGIN:VCALENDAR\r\n" +
+				"VERSION:2.0\r\n" +
+				"PRODID:-//Stark Industries//On-call//EN\r\n" +
+				"BEGIN:VTIMEZONE\r\n" +
+				"TZID:America/New_York\r\n" +
+				"BEGIN:DAYLIGHT\r\n" +
+				"DTSTART:20070311T020000\r\n" +
+				"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU\r\n" +
+				"TZOFFSETFROM:-0500\r\n" +
+				"TZOFFSETTO:-0400\r\n" +
+				"END:DAYLIGHT\r\n" +
+				"BEGIN:STANDARD\r\n" +
+				"DTSTART:20071104T020000\r\n" +
+				"RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU\r\n" +
+				"TZOFFSETFROM:-0400\r\n" +
+				"TZOFFSETTO:-0500\r\n" +
+				"END:STANDARD\r\n" +
+				"END:VTIMEZONE\r\n" +
+				"BEGIN:VEVENT\r\n" +
+				"UID:on-call-1@stark.com\r\n" +
+				"DTSTAMP:20231001T120000Z\r\n" +
+				"DTSTART;TZID=America/New_York:20231002T090000\r\n" +
+				"DTEND;TZID=America/New_York:20231002T170000\r\n" +
+				"RRULE:FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20231231T235959Z\r\n" +
+				"EXDATE;TZID=America/New_York:20231009T090000,20231011T090000\r\n" +
+				"SUMMARY:On-call\\, primary\r\n" +
+				"DESCRIPTION:Pager: +1 555 555 0100\\nEscalate to Tony\r\n" +
+				"LOCATION:Stark Tower\r\n" +
+				"BEGIN:VALARM\r\n" +
+				"ACTION:DISPLAY\r\n" +
+				"TRIGGER:-PT15M\r\n" +
+				"SUMMARY:Alarm\r\n" +
+				"END:VALARM\r\n" +
+				"END:VEVENT\r\n" +
+				"END:VCALENDAR\r\n",
+			want: []CalendarEvent{
+				{
+					Uid:         "on-call-1@stark.com",
+					Summary:     "On-call, primary",
+					Description: "Pager: +1 555 555 0100\nEscalate to Tony",
+					Location:    "Stark Tower",
+					Start:       time.Date(2023, 10, 2, 9, 0, 0, 0, newYork),
+					End:         time.Date(2023, 10, 2, 17, 0, 0, 0, newYork),
+					Recurrence:  "FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20231231T235959Z",
+					ExDates: []time.Time{
+						time.Date(2023, 10, 9, 9, 0, 0, 0, newYork),
+						time.Date(2023, 10, 11, 9, 0, 0, 0, newYork),
+					},
+				},
+			},
+		},
+		{
+			name: "all day, utc, duration and unknown zone",
+			ics: "BEGIN:VCALENDAR\n" +
+				"VERSION:2.0\n" +
+				"BEGIN:VTIMEZONE\n" +
+				"TZID:Pacific Standard Time\n" +
+				"BEGIN:STANDARD\n" +
+				"DTSTART:16010101T020000\n" +
+				"TZOFFSETFROM:-0700\n" +
+				"TZOFFSETTO:-0800\n" +
+				"END:STANDARD\n" +
+				"BEGIN:DAYLIGHT\n" +
+				"DTSTART:16010101T020000\n" +
+				"TZOFFSETFROM:-0800\n" +
+				"TZOFFSETTO:-0700\n" +
+				"END:DAYLIGHT\n" +
+				"END:VTIMEZONE\n" +
+				"BEGIN:VEVENT\n" +
+				"UID:holiday@stark.com\n" +
+				"DTSTART;VALUE=DATE:20231225\n" +
+				"DTEND;VALUE=DATE:20231226\n" +
+				"SUMMARY:Holiday\n" +
+				"END:VEVENT\n" +
+				"BEGIN:VEVENT\n" +
+				"UID:launch@stark.com\n" +
+				"DTSTART:20231020T101246Z\n" +
+				"DURATION:PT1H30M\n" +
+				"END:VEVENT\n" +
+				"BEGIN:VEVENT\n" +
+				"UID:expo@stark.com\n" +
+				"DTSTART;TZID=Pacific Standard Time:20231101T100000\n" +
+				"END:VEVENT\n" +
+				"END:VCALENDAR\n",
+			want: []CalendarEvent{
+				{
+					Uid:     "holiday@stark.com",
+					Summary: "Holiday",
+					Start:   time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC),
+					End:     time.Date(2023, 12, 26, 0, 0, 0, 0, time.UTC),
+					AllDay:  true,
+				},
+				{
+					Uid:   "launch@stark.com",
+					Start: time.Date(2023, 10, 20, 10, 12, 46, 0, time.UTC),
+					End:   time.Date(2023, 10, 20, 11, 42, 46, 0, time.UTC),
+				},
+				{
+					Uid:   "expo@stark.com",
+					Start: time.Date(2023, 11, 1, 10, 0, 0, 0, pacific),
+				},
+			},
+		},
+		{
+			name:    "unknown zone without definition",
+			ics:     "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;TZID=Stark Standard Time:20231101T100000\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+			wantErr: `icalendar: line 3: unknown time zone "Stark Standard Time"`,
+		},
+		{
+			name:    "invalid duration",
+			ics:     "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART:20231020T101246Z\r\nDURATION:PT\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+			wantErr: `icalendar: line 4: invalid duration "PT"`,
+		},
+		{
+			name:    "mismatched end",
+			ics:     "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nEND:VTODO\r\n",
+			wantErr: "icalendar: line 3: unexpected END:VTODO",
+		},
+		{
+			name:    "missing end",
+			ics:     "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nEND:VEVENT\r\n",
+			wantErr: "icalendar: missing END:VCALENDAR",
+		},
+		{
+			name:    "outside of calendar",
+			ics:     "BEGIN:VEVENT\r\nEND:VEVENT\r\n",
+			wantErr: "icalendar: line 1: unexpected BEGIN:VEVENT outside of VCALENDAR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseICalendar(strings.NewReader(tt.ics))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(CalendarEvent{}, "Ical")); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			for i := range got {
+				if got[i].Start.Location().String() != tt.want[i].Start.Location().String() {
+					t.Fatalf("unexpected location %s", got[i].Start.Location())
+				}
+			}
+		})
+	}
+}
+
+func TestParseICalendar_Ical(t *testing.T) {
+	ics := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VTIMEZONE\r\n" +
+		"TZID:Europe/Berlin\r\n" +
+		"END:VTIMEZONE\r\n" +
+		"BEGIN:VTIMEZONE\r\n" +
+		"TZID:Asia/Tokyo\r\n" +
+		"END:VTIMEZONE\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:a@stark.com\r\n" +
+		"DTSTART;TZID=Europe/Berlin:20231020T101246\r\n" +
+		"X-STARK-PRIORITY:high\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	got, err := ParseICalendar(strings.NewReader(ics))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	want := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"PRODID:-//abagayev//go-forwardemail//EN\r\n" +
+		"BEGIN:VTIMEZONE\r\n" +
+		"TZID:Europe/Berlin\r\n" +
+		"END:VTIMEZONE\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:a@stark.com\r\n" +
+		"DTSTART;TZID=Europe/Berlin:20231020T101246\r\n" +
+		"X-STARK-PRIORITY:high\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	if diff := cmp.Diff(want, got[0].Ical); diff != "" {
+		t.Fatalf("values are not the same %s", diff)
+	}
+}
+
+func TestCalendarEvent_ICalendar(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+
+	tests := []struct {
+		name  string
+		event CalendarEvent
+		want  string
+	}{
+		{
+			name: "daylight saving zone",
+			event: CalendarEvent{
+				Uid:        "standup@stark.com",
+				Summary:    "Standup; daily",
+				Start:      time.Date(2023, 10, 20, 9, 0, 0, 0, berlin),
+				End:        time.Date(2023, 10, 20, 9, 15, 0, 0, berlin),
+				Recurrence: "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR",
+				ExDates:    []time.Time{time.Date(2023, 12, 25, 9, 0, 0, 0, berlin)},
+				UpdatedAt:  parseTime("2023-10-07T21:21:01.992Z"),
+			},
+			want: "BEGIN:VCALENDAR\r\n" +
+				"VERSION:2.0\r\n" +
+				"PRODID:-//abagayev//go-forwardemail//EN\r\n" +
+				"CALSCALE:GREGORIAN\r\n" +
+				"BEGIN:VTIMEZONE\r\n" +
+				"TZID:Europe/Berlin\r\n" +
+				"BEGIN:DAYLIGHT\r\n" +
+				"DTSTART:20230326T020000\r\n" +
+				"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU\r\n" +
+				"TZOFFSETFROM:+0100\r\n" +
+				"TZOFFSETTO:+0200\r\n" +
+				"TZNAME:CEST\r\n" +
+				"END:DAYLIGHT\r\n" +
+				"BEGIN:STANDARD\r\n" +
+				"DTSTART:20231029T030000\r\n" +
+				"RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU\r\n" +
+				"TZOFFSETFROM:+0200\r\n" +
+				"TZOFFSETTO:+0100\r\n" +
+				"TZNAME:CET\r\n" +
+				"END:STANDARD\r\n" +
+				"END:VTIMEZONE\r\n" +
+				"BEGIN:VEVENT\r\n" +
+				"UID:standup@stark.com\r\n" +
+				"DTSTAMP:20231007T212101Z\r\n" +
+				"DTSTART;TZID=Europe/Berlin:20231020T090000\r\n" +
+				"DTEND;TZID=Europe/Berlin:20231020T091500\r\n" +
+				"RRULE:FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR\r\n" +
+				"EXDATE;TZID=Europe/Berlin:20231225T090000\r\n" +
+				"SUMMARY:Standup\\; daily\r\n" +
+				"END:VEVENT\r\n" +
+				"END:VCALENDAR\r\n",
+		},
+		{
+			name: "fixed zone and utc",
+			event: CalendarEvent{
+				Uid:       "launch@stark.com",
+				Start:     time.Date(2023, 10, 20, 9, 0, 0, 0, tokyo),
+				End:       time.Date(2023, 10, 20, 3, 0, 0, 0, time.UTC),
+				UpdatedAt: parseTime("2023-10-07T21:21:01.992Z"),
+			},
+			want: "BEGIN:VCALENDAR\r\n" +
+				"VERSION:2.0\r\n" +
+				"PRODID:-//abagayev//go-forwardemail//EN\r\n" +
+				"CALSCALE:GREGORIAN\r\n" +
+				"BEGIN:VTIMEZONE\r\n" +
+				"TZID:Asia/Tokyo\r\n" +
+				"BEGIN:STANDARD\r\n" +
+				"DTSTART:19700101T000000\r\n" +
+				"TZOFFSETFROM:+0900\r\n" +
+				"TZOFFSETTO:+0900\r\n" +
+				"TZNAME:JST\r\n" +
+				"END:STANDARD\r\n" +
+				"END:VTIMEZONE\r\n" +
+				"BEGIN:VEVENT\r\n" +
+				"UID:launch@stark.com\r\n" +
+				"DTSTAMP:20231007T212101Z\r\n" +
+				"DTSTART;TZID=Asia/Tokyo:20231020T090000\r\n" +
+				"DTEND:20231020T030000Z\r\n" +
+				"END:VEVENT\r\n" +
+				"END:VCALENDAR\r\n",
+		},
+		{
+			name: "all day",
+			event: CalendarEvent{
+				Uid:       "holiday@stark.com",
+				Summary:   "Holiday",
+				Start:     time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC),
+				End:       time.Date(2023, 12, 26, 0, 0, 0, 0, time.UTC),
+				AllDay:    true,
+				UpdatedAt: parseTime("2023-10-07T21:21:01.992Z"),
+			},
+			want: "BEGIN:VCALENDAR\r\n" +
+				"VERSION:2.0\r\n" +
+				"PRODID:-//abagayev//go-forwardemail//EN\r\n" +
+				"CALSCALE:GREGORIAN\r\n" +
+				"BEGIN:VEVENT\r\n" +
+				"UID:holiday@stark.com\r\n" +
+				"DTSTAMP:20231007T212101Z\r\n" +
+				"DTSTART;VALUE=DATE:20231225\r\n" +
+				"DTEND;VALUE=DATE:20231226\r\n" +
+				"SUMMARY:Holiday\r\n" +
+				"END:VEVENT\r\n" +
+				"END:VCALENDAR\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.event.ICalendar()
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestICalendar_RoundTrip(t *testing.T) {
+	sydney, _ := time.LoadLocation("Australia/Sydney")
+
+	events := []CalendarEvent{
+		{
+			Uid:         "on-call@stark.com",
+			Summary:     "On-call: Тони",
+			Description: "Line one\nLine two, with comma; and semicolon",
+			Location:    "Stark Tower, NYC",
+			Start:       time.Date(2024, 1, 15, 9, 0, 0, 0, sydney),
+			End:         time.Date(2024, 1, 15, 17, 0, 0, 0, sydney),
+			Recurrence:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO;COUNT=10",
+			ExDates:     []time.Time{time.Date(2024, 1, 29, 9, 0, 0, 0, sydney)},
+		},
+		{
+			Uid:    "holiday@stark.com",
+			Start:  time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC),
+			AllDay: true,
+		},
+		{
+			Uid:     "standup@stark.com",
+			Summary: "Stand-up wherever you are",
+			Start:   time.Date(2024, 3, 10, 9, 0, 0, 0, ICalFloating),
+			End:     time.Date(2024, 3, 10, 9, 15, 0, 0, ICalFloating),
+		},
+	}
+
+	var b strings.Builder
+	if err := WriteICalendar(&b, events); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for _, line := range strings.Split(b.String(), "\r\n") {
+		if len(line) > 75 {
+			t.Fatalf("line is too long: %q", line)
+		}
+	}
+
+	got, err := ParseICalendar(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if diff := cmp.Diff(events, got, cmpopts.IgnoreFields(CalendarEvent{}, "Ical")); diff != "" {
+		t.Fatalf("values are not the same %s", diff)
+	}
+	if loc := got[0].Start.Location().String(); loc != "Australia/Sydney" {
+		t.Fatalf("unexpected location %s", loc)
+	}
+	if loc := got[2].Start.Location(); loc != ICalFloating {
+		t.Fatalf("unexpected location %s", loc)
+	}
+	if !strings.Contains(b.String(), "DTSTART:20240310T090000\r\nDTEND:20240310T091500\r\n") {
+		t.Fatalf("floating times were not kept %s", b.String())
+	}
+	if !strings.Contains(b.String(), "BEGIN:DAYLIGHT\r\nDTSTART:20241006T020000\r\nRRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=1SU\r\n") {
+		t.Fatalf("unexpected time zone definition %s", b.String())
+	}
+}
+
+func TestICalendar_RoundTripExtras(t *testing.T) {
+	ics := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"PRODID:-//Stark Industries//Calendar//EN\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:review@stark.com\r\n" +
+		"DTSTAMP:20231001T080000Z\r\n" +
+		"DTSTART;TZID=Europe/Berlin:20231002T100000\r\n" +
+		"DTEND;TZID=Europe/Berlin:20231002T110000\r\n" +
+		"RRULE:FREQ=WEEKLY;COUNT=4\r\n" +
+		"RDATE;TZID=Europe/Berlin:20231028T100000\r\n" +
+		"SUMMARY:Armor review\r\n" +
+		"ORGANIZER;CN=Tony Stark:mailto:tony@stark.com\r\n" +
+		"ATTENDEE;CN=James Rhodes;PARTSTAT=ACCEPTED:mailto:james@rhodes.com\r\n" +
+		"SEQUENCE:2\r\n" +
+		"STATUS:CONFIRMED\r\n" +
+		"BEGIN:VALARM\r\n" +
+		"ACTION:DISPLAY\r\n" +
+		"DESCRIPTION:Suit up\r\n" +
+		"TRIGGER:-PT15M\r\n" +
+		"END:VALARM\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:review@stark.com\r\n" +
+		"DTSTAMP:20231005T080000Z\r\n" +
+		"RECURRENCE-ID;TZID=Europe/Berlin:20231009T100000\r\n" +
+		"DTSTART;TZID=Europe/Berlin:20231009T140000\r\n" +
+		"DTEND;TZID=Europe/Berlin:20231009T150000\r\n" +
+		"SUMMARY:Armor review (moved)\r\n" +
+		"SEQUENCE:3\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	events, err := ParseICalendar(strings.NewReader(ics))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	var b strings.Builder
+	if err := WriteICalendar(&b, events); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	want := "BEGIN:VEVENT\r\n" +
+		"UID:review@stark.com\r\n" +
+		"DTSTAMP:20231001T080000Z\r\n" +
+		"DTSTART;TZID=Europe/Berlin:20231002T100000\r\n" +
+		"DTEND;TZID=Europe/Berlin:20231002T110000\r\n" +
+		"RRULE:FREQ=WEEKLY;COUNT=4\r\n" +
+		"SUMMARY:Armor review\r\n" +
+		"RDATE;TZID=Europe/Berlin:20231028T100000\r\n" +
+		"ORGANIZER;CN=Tony Stark:mailto:tony@stark.com\r\n" +
+		"ATTENDEE;CN=James Rhodes;PARTSTAT=ACCEPTED:mailto:james@rhodes.com\r\n" +
+		"SEQUENCE:2\r\n" +
+		"STATUS:CONFIRMED\r\n" +
+		"BEGIN:VALARM\r\n" +
+		"ACTION:DISPLAY\r\n" +
+		"DESCRIPTION:Suit up\r\n" +
+		"TRIGGER:-PT15M\r\n" +
+		"END:VALARM\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:review@stark.com\r\n" +
+		"DTSTAMP:20231005T080000Z\r\n" +
+		"DTSTART;TZID=Europe/Berlin:20231009T140000\r\n" +
+		"RECURRENCE-ID;TZID=Europe/Berlin:20231009T100000\r\n" +
+		"DTEND;TZID=Europe/Berlin:20231009T150000\r\n" +
+		"SUMMARY:Armor review (moved)\r\n" +
+		"SEQUENCE:3\r\n" +
+		"END:VEVENT\r\n"
+	if !strings.Contains(b.String(), want) {
+		t.Fatalf("unexpected events %s", b.String())
+	}
+
+	got, err := ParseICalendar(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if diff := cmp.Diff(events, got, cmpopts.IgnoreFields(CalendarEvent{}, "Ical")); diff != "" {
+		t.Fatalf("values are not the same %s", diff)
+	}
+	if !got[0].RecurrenceId.IsZero() || got[1].RecurrenceId.IsZero() {
+		t.Fatalf("unexpected recurrence ids %s, %s", got[0].RecurrenceId, got[1].RecurrenceId)
+	}
+
+	var again strings.Builder
+	if err := WriteICalendar(&again, got); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if diff := cmp.Diff(b.String(), again.String()); diff != "" {
+		t.Fatalf("second round trip is not the same %s", diff)
+	}
+}
@@ -0,0 +1,161 @@
+package forwardemail
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type LogEntry struct {
+	Id             string    `json:"id"`
+	Message        string    `json:"message"`
+	MessageId      string    `json:"message_id"`
+	From           string    `json:"from"`
+	Recipient      string    `json:"recipient"`
+	Subject        string    `json:"subject"`
+	BounceCategory string    `json:"bounce_category"`
+	ResponseCode   int       `json:"response_code"`
+	Domains        []string  `json:"domains"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type LogsParameters struct {
+	Domain         *string
+	Q              *string
+	BounceCategory *string
+	ResponseCode   *int
+}
+
+// LogIterator decodes log entries one at a time straight from the response
+// body. It accepts both a JSON array and newline delimited JSON objects.
+type LogIterator struct {
+	body    io.ReadCloser
+	reader  *bufio.Reader
+	decoder *json.Decoder
+	started bool
+	array   bool
+	entry   LogEntry
+	err     error
+}
+
+// DownloadLogs streams the account logs matching the parameters. The returned
+// iterator must be closed.
+func (c *Client) DownloadLogs(parameters LogsParameters) (*LogIterator, error) {
+	return c.DownloadLogsContext(context.Background(), parameters)
+}
+
+// DownloadLogsContext is like DownloadLogs but carries ctx with the request.
+func (c *Client) DownloadLogsContext(ctx context.Context, parameters LogsParameters) (*LogIterator, error) {
+	req, err := c.newRequest(ctx, "GET", "/v1/logs/download")
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+
+	for k, v := range map[string]*string{
+		"domain":          parameters.Domain,
+		"q":               parameters.Q,
+		"bounce_category": parameters.BounceCategory,
+	} {
+		if v != nil {
+			params.Add(k, *v)
+		}
+	}
+
+	if parameters.ResponseCode != nil {
+		params.Add("response_code", strconv.Itoa(*parameters.ResponseCode))
+	}
+
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.doStreamRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(res.Body)
+
+	return &LogIterator{
+		body:    res.Body,
+		reader:  reader,
+		decoder: json.NewDecoder(reader),
+	}, nil
+}
+
+// Next advances to the next entry. It returns false at the end of the stream
+// or on error, which is then available from Err.
+func (it *LogIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	if !it.started {
+		it.started = true
+		if err := it.start(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+
+	if it.array && !it.decoder.More() {
+		return false
+	}
+
+	var entry LogEntry
+	if err := it.decoder.Decode(&entry); err != nil {
+		if !errors.Is(err, io.EOF) || it.array {
+			it.err = err
+		}
+		return false
+	}
+
+	it.entry = entry
+
+	return true
+}
+
+// start detects whether the stream is a JSON array by peeking at the first
+// non-space byte.
+func (it *LogIterator) start() error {
+	for {
+		b, err := it.reader.Peek(1)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = it.reader.ReadByte()
+			continue
+		case '[':
+			if _, err := it.decoder.Token(); err != nil {
+				return err
+			}
+			it.array = true
+		}
+
+		return nil
+	}
+}
+
+func (it *LogIterator) Entry() LogEntry {
+	return it.entry
+}
+
+func (it *LogIterator) Err() error {
+	return it.err
+}
+
+func (it *LogIterator) Close() error {
+	return it.body.Close()
+}
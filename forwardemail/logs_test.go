@@ -0,0 +1,211 @@
+package forwardemail
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient_DownloadLogs(t *testing.T) {
+	tests := []struct {
+		name       string
+		parameters LogsParameters
+		response   string
+		wantQuery  url.Values
+		want       []LogEntry
+		wantErr    bool
+	}{
+		{
+			name:      "no data",
+			wantQuery: url.Values{},
+		},
+		{
+			name: "json array",
+			parameters: LogsParameters{
+				Domain:         pointString("stark.com"),
+				Q:              pointString("james"),
+				BounceCategory: pointString("block"),
+				ResponseCode:   pointInt(550),
+			},
+			response: `[
+				{
+				  "id": "6531b03e0bde8f333ace6001",
+				  "message": "550 5.7.1 blocked",
+				  "from": "tony@stark.com",
+				  "recipient": "james@rhodes.com",
+				  "bounce_category": "block",
+				  "response_code": 550,
+				  "domains": ["stark.com"],
+				  "created_at": "2023-10-20T10:12:46.588Z"
+				},
+				{
+				  "id": "6531b03e0bde8f333ace6002",
+				  "message": "550 5.1.1 unknown user",
+				  "recipient": "james@rhodes.com",
+				  "bounce_category": "block",
+				  "response_code": 550,
+				  "created_at": "2023-10-21T10:12:46.588Z"
+				}
+			]`,
+			wantQuery: url.Values{
+				"domain":          {"stark.com"},
+				"q":               {"james"},
+				"bounce_category": {"block"},
+				"response_code":   {"550"},
+			},
+			want: []LogEntry{
+				{
+					Id:             "6531b03e0bde8f333ace6001",
+					Message:        "550 5.7.1 blocked",
+					From:           "tony@stark.com",
+					Recipient:      "james@rhodes.com",
+					BounceCategory: "block",
+					ResponseCode:   550,
+					Domains:        []string{"stark.com"},
+					CreatedAt:      parseTime("2023-10-20T10:12:46.588Z"),
+				},
+				{
+					Id:             "6531b03e0bde8f333ace6002",
+					Message:        "550 5.1.1 unknown user",
+					Recipient:      "james@rhodes.com",
+					BounceCategory: "block",
+					ResponseCode:   550,
+					CreatedAt:      parseTime("2023-10-21T10:12:46.588Z"),
+				},
+			},
+		},
+		{
+			name: "newline delimited",
+			response: `{"id": "6531b03e0bde8f333ace6001", "bounce_category": "virus"}
+{"id": "6531b03e0bde8f333ace6002", "bounce_category": "spam"}
+`,
+			wantQuery: url.Values{},
+			want: []LogEntry{
+				{Id: "6531b03e0bde8f333ace6001", BounceCategory: "virus"},
+				{Id: "6531b03e0bde8f333ace6002", BounceCategory: "spam"},
+			},
+		},
+		{
+			name:      "truncated array",
+			response:  `[{"id": "6531b03e0bde8f333ace6001"}, {"id": "65`,
+			wantQuery: url.Values{},
+			want: []LogEntry{
+				{Id: "6531b03e0bde8f333ace6001"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotQuery url.Values
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotQuery = r.URL.Query()
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			it, err := c.DownloadLogs(tt.parameters)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer it.Close()
+
+			var got []LogEntry
+			for it.Next() {
+				got = append(got, it.Entry())
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if (it.Err() != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", it.Err())
+			}
+			if diff := cmp.Diff(tt.wantQuery, gotQuery); diff != "" {
+				t.Fatalf("queries are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_DownloadLogsError(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "oh no")
+	}))
+	defer svr.Close()
+
+	c := NewClient(ClientOptions{
+		ApiUrl: svr.URL,
+	})
+
+	_, err := c.DownloadLogs(LogsParameters{})
+	if diff := cmp.Diff(&APIError{StatusCode: 500, Body: []byte("oh no")}, err, cmp.Comparer(equateErrorMessage)); diff != "" {
+		t.Fatalf("values are not the same %s", diff)
+	}
+}
+
+func TestClient_DownloadLogsStreams(t *testing.T) {
+	received := make(chan struct{})
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[{"id": "first"}`)
+		w.(http.Flusher).Flush()
+
+		// The rest of the export is only written once the client has
+		// decoded the first entry, which proves nothing is buffered.
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			return
+		}
+
+		for i := 0; i < 1000; i++ {
+			fmt.Fprintf(w, `, {"id": "%d"}`, i)
+		}
+		fmt.Fprintf(w, `]`)
+	}))
+	defer svr.Close()
+
+	c := NewClient(ClientOptions{
+		ApiUrl: svr.URL,
+	})
+
+	it, err := c.DownloadLogs(LogsParameters{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer it.Close()
+
+	if !it.Next() || it.Entry().Id != "first" {
+		t.Fatalf("unexpected first entry %+v, error: %v", it.Entry(), it.Err())
+	}
+	close(received)
+
+	count := 1
+	for it.Next() {
+		count++
+	}
+
+	if it.Err() != nil {
+		t.Fatalf("unexpected error: %v", it.Err())
+	}
+	if count != 1001 {
+		t.Fatalf("unexpected number of entries %d", count)
+	}
+}
+
+func pointInt(i int) *int {
+	return &i
+}
@@ -0,0 +1,165 @@
+package forwardemail
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	MemberGroupAdmin = "admin"
+	MemberGroupUser  = "user"
+)
+
+type Member struct {
+	Account Account `json:"user"`
+	Group   string  `json:"group"`
+}
+
+type Invite struct {
+	Email     string    `json:"email"`
+	Group     string    `json:"group"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (c *Client) GetDomainMembers(domain string) ([]Member, error) {
+	return c.GetDomainMembersContext(context.Background(), domain)
+}
+
+// GetDomainMembersContext is like GetDomainMembers but carries ctx with the
+// request.
+func (c *Client) GetDomainMembersContext(ctx context.Context, domain string) ([]Member, error) {
+	item, err := c.GetDomainContext(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.Members, nil
+}
+
+func (c *Client) UpdateDomainMember(domain string, member string, group string) (*Domain, error) {
+	return c.UpdateDomainMemberContext(context.Background(), domain, member, group)
+}
+
+// UpdateDomainMemberContext is like UpdateDomainMember but carries ctx with the
+// request.
+func (c *Client) UpdateDomainMemberContext(ctx context.Context, domain string, member string, group string) (*Domain, error) {
+	req, err := c.newRequest(ctx, "PUT", fmt.Sprintf("/v1/domains/%s/members/%s", domain, member))
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Add("group", group)
+
+	setFormBody(req, params)
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item Domain
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) DeleteDomainMember(domain string, member string) error {
+	return c.DeleteDomainMemberContext(context.Background(), domain, member)
+}
+
+// DeleteDomainMemberContext is like DeleteDomainMember but carries ctx with the
+// request.
+func (c *Client) DeleteDomainMemberContext(ctx context.Context, domain string, member string) error {
+	req, err := c.newRequest(ctx, "DELETE", fmt.Sprintf("/v1/domains/%s/members/%s", domain, member))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) GetDomainInvites(domain string) ([]Invite, error) {
+	return c.GetDomainInvitesContext(context.Background(), domain)
+}
+
+// GetDomainInvitesContext is like GetDomainInvites but carries ctx with the
+// request.
+func (c *Client) GetDomainInvitesContext(ctx context.Context, domain string) ([]Invite, error) {
+	item, err := c.GetDomainContext(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.Invites, nil
+}
+
+func (c *Client) CreateDomainInvite(domain string, email string, group string) (*Domain, error) {
+	return c.CreateDomainInviteContext(context.Background(), domain, email, group)
+}
+
+// CreateDomainInviteContext is like CreateDomainInvite but carries ctx with the
+// request.
+func (c *Client) CreateDomainInviteContext(ctx context.Context, domain string, email string, group string) (*Domain, error) {
+	req, err := c.newRequest(ctx, "POST", fmt.Sprintf("/v1/domains/%s/invites", domain))
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Add("email", email)
+	params.Add("group", group)
+
+	setFormBody(req, params)
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item Domain
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) DeleteDomainInvite(domain string, email string) error {
+	return c.DeleteDomainInviteContext(context.Background(), domain, email)
+}
+
+// DeleteDomainInviteContext is like DeleteDomainInvite but carries ctx with the
+// request.
+func (c *Client) DeleteDomainInviteContext(ctx context.Context, domain string, email string) error {
+	req, err := c.newRequest(ctx, "DELETE", fmt.Sprintf("/v1/domains/%s/invites", domain))
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Add("email", email)
+
+	setFormBody(req, params)
+
+	_, err = c.doRequest(req)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,385 @@
+package forwardemail
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const domainWithMembersResponse = `{
+	"name": "stark.com",
+	"id": "15ff615b6180f1fc7faf40e6",
+	"object": "domain",
+	"members": [
+	  {
+		"user": {
+		  "email": "tony@stark.com",
+		  "display_name": "Tony Stark",
+		  "id": "59ad551ae6fb4a4c53427ca38079f029"
+		},
+		"group": "admin"
+	  },
+	  {
+		"user": {
+		  "email": "james@rhodes.com",
+		  "display_name": "James Rhodes",
+		  "id": "61bd8c5e6fb4a4c53427ca38079f0312"
+		},
+		"group": "user"
+	  }
+	],
+	"invites": [
+	  {
+		"email": "pepper@stark.com",
+		"group": "user",
+		"created_at": "2023-10-07T21:21:01.992Z"
+	  }
+	]
+}`
+
+var (
+	wantMembers = []Member{
+		{
+			Account: Account{
+				Email:       "tony@stark.com",
+				DisplayName: "Tony Stark",
+				Id:          "59ad551ae6fb4a4c53427ca38079f029",
+			},
+			Group: MemberGroupAdmin,
+		},
+		{
+			Account: Account{
+				Email:       "james@rhodes.com",
+				DisplayName: "James Rhodes",
+				Id:          "61bd8c5e6fb4a4c53427ca38079f0312",
+			},
+			Group: MemberGroupUser,
+		},
+	}
+	wantInvites = []Invite{
+		{
+			Email:     "pepper@stark.com",
+			Group:     MemberGroupUser,
+			CreatedAt: parseTime("2023-10-07T21:21:01.992Z"),
+		},
+	}
+)
+
+func TestClient_GetDomainMembers(t *testing.T) {
+	tests := []struct {
+		name     string
+		domain   string
+		response string
+		want     []Member
+	}{
+		{
+			name: "no data",
+		},
+		{
+			name:     "ok",
+			domain:   "stark.com",
+			response: domainWithMembersResponse,
+			want:     wantMembers,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got, _ := c.GetDomainMembers(tt.domain)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_UpdateDomainMember(t *testing.T) {
+	type request struct {
+		domain string
+		member string
+		group  string
+	}
+
+	tests := []struct {
+		name     string
+		req      request
+		response string
+		wantPath string
+		wantBody string
+		want     *Domain
+	}{
+		{
+			name:     "no data",
+			wantPath: "/v1/domains//members/",
+			wantBody: "group=",
+		},
+		{
+			name: "ok",
+			req: request{
+				domain: "stark.com",
+				member: "61bd8c5e6fb4a4c53427ca38079f0312",
+				group:  MemberGroupUser,
+			},
+			response: domainWithMembersResponse,
+			wantPath: "/v1/domains/stark.com/members/61bd8c5e6fb4a4c53427ca38079f0312",
+			wantBody: "group=user",
+			want: &Domain{
+				Name:    "stark.com",
+				Id:      "15ff615b6180f1fc7faf40e6",
+				Object:  "domain",
+				Members: wantMembers,
+				Invites: wantInvites,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath, gotBody string
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				gotPath, gotBody = r.URL.Path, string(body)
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got, _ := c.UpdateDomainMember(tt.req.domain, tt.req.member, tt.req.group)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if diff := cmp.Diff([]string{tt.wantPath, tt.wantBody}, []string{gotPath, gotBody}); diff != "" {
+				t.Fatalf("requests are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteDomainMember(t *testing.T) {
+	type response struct {
+		code int
+		body string
+	}
+
+	tests := []struct {
+		name   string
+		domain string
+		member string
+		resp   response
+		want   error
+	}{
+		{
+			name:   "ok",
+			domain: "stark.com",
+			member: "61bd8c5e6fb4a4c53427ca38079f0312",
+			resp: response{
+				code: http.StatusOK,
+			},
+		},
+		{
+			name: "not ok",
+			resp: response{
+				code: http.StatusInternalServerError,
+				body: "oh no",
+			},
+			want: &APIError{StatusCode: 500, Body: []byte("oh no")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.resp.code)
+				fmt.Fprintf(w, tt.resp.body)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got := c.DeleteDomainMember(tt.domain, tt.member)
+			if diff := cmp.Diff(tt.want, got, cmp.Comparer(equateErrorMessage)); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_GetDomainInvites(t *testing.T) {
+	tests := []struct {
+		name     string
+		domain   string
+		response string
+		want     []Invite
+	}{
+		{
+			name: "no data",
+		},
+		{
+			name:     "ok",
+			domain:   "stark.com",
+			response: domainWithMembersResponse,
+			want:     wantInvites,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got, _ := c.GetDomainInvites(tt.domain)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_CreateDomainInvite(t *testing.T) {
+	type request struct {
+		domain string
+		email  string
+		group  string
+	}
+
+	tests := []struct {
+		name     string
+		req      request
+		response string
+		wantBody string
+		want     *Domain
+	}{
+		{
+			name:     "no data",
+			wantBody: "email=&group=",
+		},
+		{
+			name: "ok",
+			req: request{
+				domain: "stark.com",
+				email:  "pepper@stark.com",
+				group:  MemberGroupUser,
+			},
+			response: domainWithMembersResponse,
+			wantBody: "email=pepper%40stark.com&group=user",
+			want: &Domain{
+				Name:    "stark.com",
+				Id:      "15ff615b6180f1fc7faf40e6",
+				Object:  "domain",
+				Members: wantMembers,
+				Invites: wantInvites,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotBody string
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				gotBody = string(body)
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got, _ := c.CreateDomainInvite(tt.req.domain, tt.req.email, tt.req.group)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, gotBody); diff != "" {
+				t.Fatalf("bodies are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteDomainInvite(t *testing.T) {
+	type response struct {
+		code int
+		body string
+	}
+
+	tests := []struct {
+		name     string
+		domain   string
+		email    string
+		resp     response
+		wantBody string
+		want     error
+	}{
+		{
+			name:   "ok",
+			domain: "stark.com",
+			email:  "pepper@stark.com",
+			resp: response{
+				code: http.StatusOK,
+			},
+			wantBody: "email=pepper%40stark.com",
+		},
+		{
+			name: "not ok",
+			resp: response{
+				code: http.StatusInternalServerError,
+				body: "oh no",
+			},
+			wantBody: "email=",
+			want:     &APIError{StatusCode: 500, Body: []byte("oh no")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotBody string
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				gotBody = string(body)
+				w.WriteHeader(tt.resp.code)
+				fmt.Fprintf(w, tt.resp.body)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got := c.DeleteDomainInvite(tt.domain, tt.email)
+			if diff := cmp.Diff(tt.want, got, cmp.Comparer(equateErrorMessage)); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, gotBody); diff != "" {
+				t.Fatalf("bodies are not the same %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package forwardemail
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	MessageFlagSeen     = `\Seen`
+	MessageFlagAnswered = `\Answered`
+	MessageFlagFlagged  = `\Flagged`
+	MessageFlagDeleted  = `\Deleted`
+	MessageFlagDraft    = `\Draft`
+)
+
+type Message struct {
+	FolderId       string    `json:"folder_id"`
+	FolderPath     string    `json:"folder_path"`
+	ThreadId       string    `json:"thread_id"`
+	Uid            int       `json:"uid"`
+	MessageId      string    `json:"message_id"`
+	Subject        string    `json:"subject"`
+	From           string    `json:"from"`
+	To             []string  `json:"to"`
+	Flags          []string  `json:"flags"`
+	IsUnread       bool      `json:"is_unread"`
+	IsFlagged      bool      `json:"is_flagged"`
+	IsDeleted      bool      `json:"is_deleted"`
+	IsDraft        bool      `json:"is_draft"`
+	HasAttachments bool      `json:"has_attachments"`
+	Size           int64     `json:"size"`
+	HeaderDate     time.Time `json:"header_date"`
+	Eml            string    `json:"eml"`
+	Id             string    `json:"id"`
+	Object         string    `json:"object"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type MessageSearchParameters struct {
+	Folder         *string
+	Q              *string
+	Subject        *string
+	From           *string
+	To             *string
+	IsUnread       *bool
+	IsFlagged      *bool
+	HasAttachments *bool
+	Since          *time.Time
+	Before         *time.Time
+}
+
+// MessageParameters appends a message when passed to CreateMessage, and
+// moves or re-flags one when passed to UpdateMessage.
+type MessageParameters struct {
+	Folder *string
+	Raw    *string
+	Flags  *[]string
+}
+
+func (c *Client) GetMessages(parameters MessageSearchParameters) ([]Message, error) {
+	return c.GetMessagesContext(context.Background(), parameters)
+}
+
+// GetMessagesContext is like GetMessages but carries ctx with the request.
+func (c *Client) GetMessagesContext(ctx context.Context, parameters MessageSearchParameters) ([]Message, error) {
+	req, err := c.newAliasRequest(ctx, "GET", "/v1/messages")
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+
+	for k, v := range map[string]*string{
+		"folder":  parameters.Folder,
+		"q":       parameters.Q,
+		"subject": parameters.Subject,
+		"from":    parameters.From,
+		"to":      parameters.To,
+	} {
+		if v != nil {
+			params.Add(k, *v)
+		}
+	}
+
+	for k, v := range map[string]*bool{
+		"is_unread":       parameters.IsUnread,
+		"is_flagged":      parameters.IsFlagged,
+		"has_attachments": parameters.HasAttachments,
+	} {
+		if v != nil {
+			params.Add(k, strconv.FormatBool(*v))
+		}
+	}
+
+	for k, v := range map[string]*time.Time{
+		"since":  parameters.Since,
+		"before": parameters.Before,
+	} {
+		if v != nil {
+			params.Add(k, v.UTC().Format(time.RFC3339))
+		}
+	}
+
+	req.URL.RawQuery = params.Encode()
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []Message
+
+	err = json.Unmarshal(res, &items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (c *Client) GetMessage(id string) (*Message, error) {
+	return c.GetMessageContext(context.Background(), id)
+}
+
+// GetMessageContext is like GetMessage but carries ctx with the request.
+func (c *Client) GetMessageContext(ctx context.Context, id string) (*Message, error) {
+	req, err := c.newAliasRequest(ctx, "GET", fmt.Sprintf("/v1/messages/%s", id))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item Message
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) CreateMessage(parameters MessageParameters) (*Message, error) {
+	return c.CreateMessageContext(context.Background(), parameters)
+}
+
+// CreateMessageContext is like CreateMessage but carries ctx with the request.
+func (c *Client) CreateMessageContext(ctx context.Context, parameters MessageParameters) (*Message, error) {
+	req, err := c.newAliasRequest(ctx, "POST", "/v1/messages")
+	if err != nil {
+		return nil, err
+	}
+
+	setFormBody(req, parameters.encode())
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item Message
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) UpdateMessage(id string, parameters MessageParameters) (*Message, error) {
+	return c.UpdateMessageContext(context.Background(), id, parameters)
+}
+
+// UpdateMessageContext is like UpdateMessage but carries ctx with the request.
+func (c *Client) UpdateMessageContext(ctx context.Context, id string, parameters MessageParameters) (*Message, error) {
+	req, err := c.newAliasRequest(ctx, "PUT", fmt.Sprintf("/v1/messages/%s", id))
+	if err != nil {
+		return nil, err
+	}
+
+	setFormBody(req, parameters.encode())
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item Message
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) DeleteMessage(id string) error {
+	return c.DeleteMessageContext(context.Background(), id)
+}
+
+// DeleteMessageContext is like DeleteMessage but carries ctx with the request.
+func (c *Client) DeleteMessageContext(ctx context.Context, id string) error {
+	req, err := c.newAliasRequest(ctx, "DELETE", fmt.Sprintf("/v1/messages/%s", id))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p MessageParameters) encode() url.Values {
+	params := url.Values{}
+
+	for k, v := range map[string]*string{
+		"folder": p.Folder,
+		"raw":    p.Raw,
+	} {
+		if v != nil {
+			params.Add(k, *v)
+		}
+	}
+
+	encodeList(params, "flags", p.Flags)
+
+	return params
+}
@@ -0,0 +1,386 @@
+package forwardemail
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient_GetMessages(t *testing.T) {
+	since := parseTime("2023-10-01T00:00:00Z")
+
+	tests := []struct {
+		name       string
+		parameters MessageSearchParameters
+		response   string
+		wantQuery  url.Values
+		want       []Message
+	}{
+		{
+			name:      "no data",
+			wantQuery: url.Values{},
+		},
+		{
+			name: "ok",
+			parameters: MessageSearchParameters{
+				Folder:   pointString("INBOX"),
+				From:     pointString("james@rhodes.com"),
+				IsUnread: pointBool(true),
+				Since:    &since,
+			},
+			response: `[
+				{
+				  "folder_id": "6531b03e0bde8f333ace7001",
+				  "folder_path": "INBOX",
+				  "uid": 42,
+				  "subject": "War Machine",
+				  "from": "james@rhodes.com",
+				  "flags": [],
+				  "is_unread": true,
+				  "size": 2048,
+				  "header_date": "2023-10-20T10:12:46.000Z",
+				  "id": "6531b03e0bde8f333ace7101",
+				  "object": "message",
+				  "created_at": "2023-10-20T10:12:46.588Z",
+				  "updated_at": "2023-10-20T10:12:46.588Z"
+				}
+			]`,
+			wantQuery: url.Values{
+				"folder":    {"INBOX"},
+				"from":      {"james@rhodes.com"},
+				"is_unread": {"true"},
+				"since":     {"2023-10-01T00:00:00Z"},
+			},
+			want: []Message{
+				{
+					FolderId:   "6531b03e0bde8f333ace7001",
+					FolderPath: "INBOX",
+					Uid:        42,
+					Subject:    "War Machine",
+					From:       "james@rhodes.com",
+					Flags:      []string{},
+					IsUnread:   true,
+					Size:       2048,
+					HeaderDate: parseTime("2023-10-20T10:12:46.000Z"),
+					Id:         "6531b03e0bde8f333ace7101",
+					Object:     "message",
+					CreatedAt:  parseTime("2023-10-20T10:12:46.588Z"),
+					UpdatedAt:  parseTime("2023-10-20T10:12:46.588Z"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotQuery url.Values
+			var gotUser, gotPassword string
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotQuery = r.URL.Query()
+				gotUser, gotPassword, _ = r.BasicAuth()
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				ApiKey:        "4e4d6c332b6fe62a63afe56171fd3725",
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			})
+
+			got, _ := c.GetMessages(tt.parameters)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantQuery, gotQuery); diff != "" {
+				t.Fatalf("queries are not the same %s", diff)
+			}
+			if gotUser != "tony@stark.com" || gotPassword != "i-am-iron-man" {
+				t.Fatalf("unexpected credentials %s:%s", gotUser, gotPassword)
+			}
+		})
+	}
+}
+
+func TestClient_GetMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		response string
+		want     *Message
+	}{
+		{
+			name: "no data",
+		},
+		{
+			name: "ok",
+			id:   "6531b03e0bde8f333ace7101",
+			response: `{
+				"folder_path": "INBOX",
+				"subject": "War Machine",
+				"flags": ["\\Seen"],
+				"eml": "From: james@rhodes.com\r\nSubject: War Machine\r\n\r\nReady",
+				"id": "6531b03e0bde8f333ace7101",
+				"object": "message"
+			}`,
+			want: &Message{
+				FolderPath: "INBOX",
+				Subject:    "War Machine",
+				Flags:      []string{MessageFlagSeen},
+				Eml:        "From: james@rhodes.com\r\nSubject: War Machine\r\n\r\nReady",
+				Id:         "6531b03e0bde8f333ace7101",
+				Object:     "message",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			})
+
+			got, _ := c.GetMessage(tt.id)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_CreateMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		parameters MessageParameters
+		response   string
+		wantForm   url.Values
+		want       *Message
+	}{
+		{
+			name:     "no data",
+			wantForm: url.Values{},
+		},
+		{
+			name: "ok",
+			parameters: MessageParameters{
+				Folder: pointString("Drafts"),
+				Raw:    pointString("From: tony@stark.com\r\nSubject: Draft\r\n\r\nTBD"),
+				Flags:  pointSliceOfStrings([]string{MessageFlagDraft}),
+			},
+			response: `{
+				"folder_path": "Drafts",
+				"subject": "Draft",
+				"flags": ["\\Draft"],
+				"is_draft": true,
+				"id": "6531b03e0bde8f333ace7102",
+				"object": "message"
+			}`,
+			wantForm: url.Values{
+				"folder":  {"Drafts"},
+				"raw":     {"From: tony@stark.com\r\nSubject: Draft\r\n\r\nTBD"},
+				"flags[]": {`\Draft`},
+			},
+			want: &Message{
+				FolderPath: "Drafts",
+				Subject:    "Draft",
+				Flags:      []string{MessageFlagDraft},
+				IsDraft:    true,
+				Id:         "6531b03e0bde8f333ace7102",
+				Object:     "message",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotForm url.Values
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				gotForm = r.PostForm
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			})
+
+			got, _ := c.CreateMessage(tt.parameters)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantForm, gotForm); diff != "" {
+				t.Fatalf("form values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_UpdateMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		parameters MessageParameters
+		response   string
+		wantPath   string
+		wantForm   url.Values
+		want       *Message
+	}{
+		{
+			name:     "no data",
+			wantPath: "/v1/messages/",
+			wantForm: url.Values{},
+		},
+		{
+			name: "move and flag",
+			id:   "6531b03e0bde8f333ace7101",
+			parameters: MessageParameters{
+				Folder: pointString("Archive/2023"),
+				Flags:  pointSliceOfStrings([]string{MessageFlagSeen, MessageFlagFlagged}),
+			},
+			response: `{
+				"folder_path": "Archive/2023",
+				"flags": ["\\Seen", "\\Flagged"],
+				"is_flagged": true,
+				"id": "6531b03e0bde8f333ace7101",
+				"object": "message",
+				"updated_at": "2023-10-21T10:12:46.588Z"
+			}`,
+			wantPath: "/v1/messages/6531b03e0bde8f333ace7101",
+			wantForm: url.Values{
+				"folder":  {"Archive/2023"},
+				"flags[]": {`\Seen`, `\Flagged`},
+			},
+			want: &Message{
+				FolderPath: "Archive/2023",
+				Flags:      []string{MessageFlagSeen, MessageFlagFlagged},
+				IsFlagged:  true,
+				Id:         "6531b03e0bde8f333ace7101",
+				Object:     "message",
+				UpdatedAt:  parseTime("2023-10-21T10:12:46.588Z"),
+			},
+		},
+		{
+			name: "mark unread",
+			id:   "6531b03e0bde8f333ace7101",
+			parameters: MessageParameters{
+				Flags: pointSliceOfStrings([]string{}),
+			},
+			response: `{
+				"folder_path": "INBOX",
+				"flags": [],
+				"id": "6531b03e0bde8f333ace7101",
+				"object": "message",
+				"updated_at": "2023-10-21T10:12:46.588Z"
+			}`,
+			wantPath: "/v1/messages/6531b03e0bde8f333ace7101",
+			wantForm: url.Values{
+				"flags": {""},
+			},
+			want: &Message{
+				FolderPath: "INBOX",
+				Flags:      []string{},
+				Id:         "6531b03e0bde8f333ace7101",
+				Object:     "message",
+				UpdatedAt:  parseTime("2023-10-21T10:12:46.588Z"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			var gotForm url.Values
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				gotPath, gotForm = r.URL.Path, r.PostForm
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			})
+
+			got, _ := c.UpdateMessage(tt.id, tt.parameters)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if gotPath != tt.wantPath {
+				t.Fatalf("unexpected path %s", gotPath)
+			}
+			if diff := cmp.Diff(tt.wantForm, gotForm); diff != "" {
+				t.Fatalf("form values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteMessage(t *testing.T) {
+	type response struct {
+		code int
+		body string
+	}
+
+	tests := []struct {
+		name string
+		id   string
+		resp response
+		want error
+	}{
+		{
+			name: "ok",
+			resp: response{
+				code: http.StatusNoContent,
+			},
+		},
+		{
+			name: "not ok",
+			resp: response{
+				code: http.StatusInternalServerError,
+				body: "oh no",
+			},
+			want: &APIError{StatusCode: 500, Body: []byte("oh no")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.resp.code)
+				fmt.Fprintf(w, tt.resp.body)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:        svr.URL,
+				AliasUsername: "tony@stark.com",
+				AliasPassword: "i-am-iron-man",
+			})
+
+			got := c.DeleteMessage(tt.id)
+			if diff := cmp.Diff(tt.want, got, cmp.Comparer(equateErrorMessage)); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,527 @@
+// Package mime composes RFC 5322 messages with RFC 2045 MIME bodies, ready to
+// be sent through the Forward Email API as a raw payload.
+package mime
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	stdmime "mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	maxLineLength = 76
+	// maxHeaderLength is the line length RFC 5322 recommends for headers.
+	maxHeaderLength = 78
+	// maxParamLength is the longest encoded parameter value written on a
+	// single folded line.
+	maxParamLength = 40
+	crlf           = "\r\n"
+)
+
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+
+	// ContentId makes the attachment inline, so it can be referenced from
+	// the HTML body as "cid:<ContentId>".
+	ContentId string
+}
+
+type Message struct {
+	From *mail.Address
+	To   []mail.Address
+	Cc   []mail.Address
+	// Bcc is envelope data only and is never written to the message, so pass
+	// it separately, for example as EmailParameters.Bcc.
+	Bcc        []mail.Address
+	ReplyTo    []mail.Address
+	Subject    string
+	Date       time.Time
+	MessageId  string
+	InReplyTo  string
+	References []string
+	Headers    map[string]string
+
+	Text        string
+	Html        string
+	Attachments []Attachment
+}
+
+// NewMessage returns an empty message sent from the given address.
+func NewMessage(from mail.Address) *Message {
+	return &Message{
+		From:    &from,
+		Headers: map[string]string{},
+	}
+}
+
+func (m *Message) AddTo(addresses ...mail.Address) *Message {
+	m.To = append(m.To, addresses...)
+	return m
+}
+
+func (m *Message) AddCc(addresses ...mail.Address) *Message {
+	m.Cc = append(m.Cc, addresses...)
+	return m
+}
+
+func (m *Message) AddBcc(addresses ...mail.Address) *Message {
+	m.Bcc = append(m.Bcc, addresses...)
+	return m
+}
+
+func (m *Message) AddReplyTo(addresses ...mail.Address) *Message {
+	m.ReplyTo = append(m.ReplyTo, addresses...)
+	return m
+}
+
+func (m *Message) SetSubject(subject string) *Message {
+	m.Subject = subject
+	return m
+}
+
+func (m *Message) SetText(text string) *Message {
+	m.Text = text
+	return m
+}
+
+func (m *Message) SetHtml(html string) *Message {
+	m.Html = html
+	return m
+}
+
+// SetHeader sets a custom header. Names that are not valid RFC 5322 field
+// names, or that the builder writes itself such as From or Subject, make
+// rendering fail.
+func (m *Message) SetHeader(key, value string) *Message {
+	if m.Headers == nil {
+		m.Headers = map[string]string{}
+	}
+	m.Headers[textproto.CanonicalMIMEHeaderKey(key)] = value
+	return m
+}
+
+func (m *Message) Attach(filename, contentType string, data []byte) *Message {
+	m.Attachments = append(m.Attachments, Attachment{
+		Filename:    filename,
+		ContentType: contentType,
+		Data:        data,
+	})
+	return m
+}
+
+func (m *Message) Embed(contentId, filename, contentType string, data []byte) *Message {
+	m.Attachments = append(m.Attachments, Attachment{
+		Filename:    filename,
+		ContentType: contentType,
+		Data:        data,
+		ContentId:   contentId,
+	})
+	return m
+}
+
+// Bytes renders the message. Date and Message-ID are filled in when empty.
+func (m *Message) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+
+	if _, err := m.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// String renders the message, suitable for EmailParameters.Raw.
+func (m *Message) String() (string, error) {
+	b, err := m.Bytes()
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func (m *Message) WriteTo(w io.Writer) (int64, error) {
+	if m.From == nil || m.From.Address == "" {
+		return 0, errors.New("mime: message has no sender")
+	}
+	if len(m.To)+len(m.Cc)+len(m.Bcc) == 0 {
+		return 0, errors.New("mime: message has no recipients")
+	}
+
+	for k := range m.Headers {
+		if err := checkHeaderKey(k); err != nil {
+			return 0, err
+		}
+	}
+
+	messageId := m.MessageId
+	if messageId == "" {
+		id, err := generateMessageId(m.From.Address)
+		if err != nil {
+			return 0, err
+		}
+		messageId = id
+	}
+
+	date := m.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	var buf bytes.Buffer
+
+	writeHeader(&buf, "From", m.From.String())
+	writeAddresses(&buf, "To", m.To)
+	writeAddresses(&buf, "Cc", m.Cc)
+	writeAddresses(&buf, "Reply-To", m.ReplyTo)
+	writeHeader(&buf, "Subject", encodeHeader(m.Subject))
+	writeHeader(&buf, "Date", date.Format(time.RFC1123Z))
+	writeHeader(&buf, "Message-ID", angle(messageId))
+	if m.InReplyTo != "" {
+		writeHeader(&buf, "In-Reply-To", angle(m.InReplyTo))
+	}
+	if len(m.References) > 0 {
+		refs := make([]string, len(m.References))
+		for i, r := range m.References {
+			refs[i] = angle(r)
+		}
+		writeHeader(&buf, "References", strings.Join(refs, " "))
+	}
+
+	keys := make([]string, 0, len(m.Headers))
+	for k := range m.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		writeHeader(&buf, textproto.CanonicalMIMEHeaderKey(k), encodeHeader(m.Headers[k]))
+	}
+
+	writeHeader(&buf, "MIME-Version", "1.0")
+
+	if err := m.writeBody(&buf); err != nil {
+		return 0, err
+	}
+
+	return buf.WriteTo(w)
+}
+
+// writeBody lays out the parts as multipart/mixed, containing
+// multipart/related, containing multipart/alternative, omitting every level
+// that would only have a single child.
+func (m *Message) writeBody(buf *bytes.Buffer) error {
+	var inline, attached []Attachment
+	for _, a := range m.Attachments {
+		if a.ContentId != "" {
+			inline = append(inline, a)
+		} else {
+			attached = append(attached, a)
+		}
+	}
+
+	root := m.alternative()
+	if len(inline) > 0 {
+		related := &part{multipart: "related", children: []*part{root}}
+		for _, a := range inline {
+			related.children = append(related.children, attachmentPart(a))
+		}
+		root = related
+	}
+	if len(attached) > 0 {
+		mixed := &part{multipart: "mixed", children: []*part{root}}
+		for _, a := range attached {
+			mixed.children = append(mixed.children, attachmentPart(a))
+		}
+		root = mixed
+	}
+
+	header, body, err := root.render()
+	if err != nil {
+		return err
+	}
+
+	writePartHeader(buf, header)
+	buf.WriteString(crlf)
+	if root.multipart != "" {
+		buf.WriteString("This is a multi-part message in MIME format." + crlf)
+	}
+	buf.Write(body)
+
+	return nil
+}
+
+func (m *Message) alternative() *part {
+	text := textPart("text/plain", m.Text)
+	if m.Html == "" {
+		return text
+	}
+
+	html := textPart("text/html", m.Html)
+	if m.Text == "" {
+		return html
+	}
+
+	return &part{multipart: "alternative", children: []*part{text, html}}
+}
+
+type part struct {
+	header    textproto.MIMEHeader
+	body      []byte
+	multipart string
+	children  []*part
+}
+
+func textPart(contentType, content string) *part {
+	var body bytes.Buffer
+	qp := quotedprintable.NewWriter(&body)
+	_, _ = qp.Write([]byte(normalizeNewlines(content)))
+	_ = qp.Close()
+
+	return &part{
+		header: textproto.MIMEHeader{
+			"Content-Type":              {stdmime.FormatMediaType(contentType, map[string]string{"charset": "utf-8"})},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		},
+		body: body.Bytes(),
+	}
+}
+
+func attachmentPart(a Attachment) *part {
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	disposition := "attachment"
+	if a.ContentId != "" {
+		disposition = "inline"
+	}
+
+	header := textproto.MIMEHeader{
+		"Content-Type":              {formatMediaType(contentType, "name", a.Filename)},
+		"Content-Disposition":       {formatMediaType(disposition, "filename", a.Filename)},
+		"Content-Transfer-Encoding": {"base64"},
+	}
+	if a.Filename == "" {
+		header.Set("Content-Type", contentType)
+		header.Set("Content-Disposition", disposition)
+	}
+	if a.ContentId != "" {
+		header.Set("Content-Id", angle(a.ContentId))
+	}
+
+	return &part{header: header, body: encodeBase64(a.Data)}
+}
+
+func (p *part) render() (textproto.MIMEHeader, []byte, error) {
+	if p.multipart == "" {
+		return p.header, p.body, nil
+	}
+
+	// The parts are written by hand rather than with multipart.Writer so
+	// that their headers are folded like the top-level ones.
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+
+	var body bytes.Buffer
+	for i, child := range p.children {
+		header, content, err := child.render()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if i > 0 {
+			body.WriteString(crlf)
+		}
+		body.WriteString("--" + boundary + crlf)
+		writePartHeader(&body, header)
+		body.WriteString(crlf)
+		body.Write(content)
+	}
+	body.WriteString(crlf + "--" + boundary + "--" + crlf)
+
+	contentType := stdmime.FormatMediaType("multipart/"+p.multipart, map[string]string{"boundary": boundary})
+
+	return textproto.MIMEHeader{"Content-Type": {contentType}}, body.Bytes(), nil
+}
+
+func writePartHeader(buf *bytes.Buffer, header textproto.MIMEHeader) {
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		for _, v := range header[k] {
+			writeHeader(buf, k, v)
+		}
+	}
+}
+
+func writeAddresses(buf *bytes.Buffer, key string, addresses []mail.Address) {
+	if len(addresses) == 0 {
+		return
+	}
+
+	list := make([]string, len(addresses))
+	for i, a := range addresses {
+		list[i] = a.String()
+	}
+
+	writeHeader(buf, key, strings.Join(list, ", "))
+}
+
+// writeHeader writes a header field, folding it at spaces when it does not
+// fit within the recommended 78 characters. The field name counts towards the
+// first line, which only holds the name when the first word does not fit.
+func writeHeader(buf *bytes.Buffer, key, value string) {
+	value = stripNewlines(value)
+	line := key + ": " + value
+	if len(line) <= maxHeaderLength {
+		buf.WriteString(line + crlf)
+		return
+	}
+
+	line = key + ":"
+	for _, word := range strings.Split(value, " ") {
+		if line != "" && len(line)+1+len(word) > maxHeaderLength {
+			buf.WriteString(line + crlf)
+			line = ""
+		}
+		line += " " + word
+	}
+
+	buf.WriteString(line + crlf)
+}
+
+// formatMediaType is like mime.FormatMediaType with a single parameter, but
+// splits a long value into RFC 2231 continuations so that writeHeader can
+// fold it.
+func formatMediaType(mediaType, key, value string) string {
+	formatted := stdmime.FormatMediaType(mediaType, map[string]string{key: value})
+	if formatted == "" || len(formatted)-len(mediaType) <= maxHeaderLength/2 {
+		return formatted
+	}
+
+	var encoded strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("-._", c) >= 0 {
+			encoded.WriteByte(c)
+		} else {
+			fmt.Fprintf(&encoded, "%%%02X", c)
+		}
+	}
+
+	params := []string{strings.SplitN(formatted, ";", 2)[0]}
+	rest, charset := encoded.String(), "utf-8''"
+	for n := 0; rest != ""; n++ {
+		size := min(len(rest), maxParamLength)
+		// Keep percent-encoded octets whole.
+		if i := strings.LastIndexByte(rest[:size], '%'); size < len(rest) && i >= 0 && i > size-3 {
+			size = i
+		}
+		params = append(params, fmt.Sprintf("%s*%d*=%s%s", key, n, charset, rest[:size]))
+		rest, charset = rest[size:], ""
+	}
+
+	return strings.Join(params, "; ")
+}
+
+// builderHeaders are written from the Message fields and cannot be set
+// through Headers.
+var builderHeaders = map[string]bool{
+	"From":                      true,
+	"To":                        true,
+	"Cc":                        true,
+	"Bcc":                       true,
+	"Reply-To":                  true,
+	"Subject":                   true,
+	"Date":                      true,
+	"Message-Id":                true,
+	"In-Reply-To":               true,
+	"References":                true,
+	"Mime-Version":              true,
+	"Content-Type":              true,
+	"Content-Transfer-Encoding": true,
+}
+
+// checkHeaderKey accepts RFC 5322 field names, which are printable ASCII
+// characters other than the colon.
+func checkHeaderKey(key string) error {
+	if key == "" {
+		return errors.New("mime: empty header name")
+	}
+
+	for _, c := range key {
+		if c < '!' || c > '~' || c == ':' {
+			return fmt.Errorf("mime: invalid header name %q", key)
+		}
+	}
+
+	if builderHeaders[textproto.CanonicalMIMEHeaderKey(key)] {
+		return fmt.Errorf("mime: header %q is set by the message builder", key)
+	}
+
+	return nil
+}
+
+func encodeHeader(value string) string {
+	return stdmime.QEncoding.Encode("utf-8", value)
+}
+
+func encodeBase64(data []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	var buf bytes.Buffer
+	for len(encoded) > maxLineLength {
+		buf.WriteString(encoded[:maxLineLength] + crlf)
+		encoded = encoded[maxLineLength:]
+	}
+	if encoded != "" {
+		buf.WriteString(encoded + crlf)
+	}
+
+	return buf.Bytes()
+}
+
+func normalizeNewlines(s string) string {
+	s = strings.ReplaceAll(s, crlf, "\n")
+	return strings.ReplaceAll(s, "\n", crlf)
+}
+
+func stripNewlines(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
+
+func angle(id string) string {
+	return "<" + strings.Trim(id, "<>") + ">"
+}
+
+func generateMessageId(from string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("mime: generate message id: %w", err)
+	}
+
+	domain := "localhost"
+	if _, d, ok := strings.Cut(from, "@"); ok && d != "" {
+		domain = d
+	}
+
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">", nil
+}
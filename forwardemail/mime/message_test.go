@@ -0,0 +1,380 @@
+package mime
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	stdmime "mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type parsedPart struct {
+	ContentType string
+	Params      map[string]string
+	Disposition string
+	Filename    string
+	ContentId   string
+	Body        string
+	Parts       []parsedPart
+}
+
+func TestMessage_Bytes(t *testing.T) {
+	date := time.Date(2023, 10, 20, 10, 12, 46, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		message     *Message
+		wantHeaders map[string]string
+		want        parsedPart
+	}{
+		{
+			name: "plain text",
+			message: NewMessage(mail.Address{Name: "Tony Stark", Address: "tony@stark.com"}).
+				AddTo(mail.Address{Address: "james@rhodes.com"}).
+				SetSubject("Suit").
+				SetText("Suit is ready.\nCome by."),
+			wantHeaders: map[string]string{
+				"From":    "Tony Stark <tony@stark.com>",
+				"To":      "james@rhodes.com",
+				"Subject": "Suit",
+			},
+			want: parsedPart{
+				ContentType: "text/plain",
+				Params:      map[string]string{"charset": "utf-8"},
+				Body:        "Suit is ready.\r\nCome by.",
+			},
+		},
+		{
+			name: "non-ascii headers and filenames",
+			message: NewMessage(mail.Address{Name: "Тони Старк", Address: "tony@stark.com"}).
+				AddTo(mail.Address{Name: "Джеймс", Address: "james@rhodes.com"}).
+				AddCc(mail.Address{Address: "pepper@stark.com"}).
+				SetSubject("Привет, это очень длинная тема письма, которую придётся перенести на следующую строку").
+				SetText("Здравствуй").
+				Attach("отчёт по костюму.pdf", "application/pdf", []byte("%PDF-1.4")),
+			wantHeaders: map[string]string{
+				"From":    "Тони Старк <tony@stark.com>",
+				"To":      "Джеймс <james@rhodes.com>",
+				"Cc":      "pepper@stark.com",
+				"Subject": "Привет, это очень длинная тема письма, которую придётся перенести на следующую строку",
+			},
+			want: parsedPart{
+				ContentType: "multipart/mixed",
+				Parts: []parsedPart{
+					{
+						ContentType: "text/plain",
+						Params:      map[string]string{"charset": "utf-8"},
+						Body:        "Здравствуй",
+					},
+					{
+						ContentType: "application/pdf",
+						Params:      map[string]string{"name": "отчёт по костюму.pdf"},
+						Disposition: "attachment",
+						Filename:    "отчёт по костюму.pdf",
+						Body:        "%PDF-1.4",
+					},
+				},
+			},
+		},
+		{
+			name: "alternative with inline image and attachment",
+			message: NewMessage(mail.Address{Address: "tony@stark.com"}).
+				AddTo(mail.Address{Address: "james@rhodes.com"}).
+				SetSubject("Armor").
+				SetText("See the armor").
+				SetHtml(`<p>See the <img src="cid:armor"></p>`).
+				Embed("armor", "armor.png", "image/png", []byte{0x89, 'P', 'N', 'G'}).
+				Attach("specs.txt", "text/plain", []byte("mark 42")),
+			wantHeaders: map[string]string{
+				"From":    "tony@stark.com",
+				"To":      "james@rhodes.com",
+				"Subject": "Armor",
+			},
+			want: parsedPart{
+				ContentType: "multipart/mixed",
+				Parts: []parsedPart{
+					{
+						ContentType: "multipart/related",
+						Parts: []parsedPart{
+							{
+								ContentType: "multipart/alternative",
+								Parts: []parsedPart{
+									{
+										ContentType: "text/plain",
+										Params:      map[string]string{"charset": "utf-8"},
+										Body:        "See the armor",
+									},
+									{
+										ContentType: "text/html",
+										Params:      map[string]string{"charset": "utf-8"},
+										Body:        `<p>See the <img src="cid:armor"></p>`,
+									},
+								},
+							},
+							{
+								ContentType: "image/png",
+								Params:      map[string]string{"name": "armor.png"},
+								Disposition: "inline",
+								Filename:    "armor.png",
+								ContentId:   "<armor>",
+								Body:        string([]byte{0x89, 'P', 'N', 'G'}),
+							},
+						},
+					},
+					{
+						ContentType: "text/plain",
+						Params:      map[string]string{"name": "specs.txt"},
+						Disposition: "attachment",
+						Filename:    "specs.txt",
+						Body:        "mark 42",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.message.Date = date
+
+			raw, err := tt.message.Bytes()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, line := range strings.Split(string(raw), "\r\n") {
+				if len(line) > 78 {
+					t.Fatalf("line is too long: %d %q", len(line), line)
+				}
+			}
+
+			msg, err := mail.ReadMessage(bytes.NewReader(raw))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			decoder := new(stdmime.WordDecoder)
+			gotHeaders := map[string]string{}
+			for k := range tt.wantHeaders {
+				switch k {
+				case "From", "To", "Cc":
+					addresses, err := msg.Header.AddressList(k)
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+					list := make([]string, len(addresses))
+					for i, a := range addresses {
+						list[i] = a.Address
+						if a.Name != "" {
+							list[i] = a.Name + " <" + a.Address + ">"
+						}
+					}
+					gotHeaders[k] = strings.Join(list, ", ")
+				default:
+					gotHeaders[k], _ = decoder.DecodeHeader(msg.Header.Get(k))
+				}
+			}
+			if diff := cmp.Diff(tt.wantHeaders, gotHeaders); diff != "" {
+				t.Fatalf("headers are not the same %s", diff)
+			}
+
+			if got, _ := msg.Header.Date(); !got.Equal(date) {
+				t.Fatalf("unexpected date %s", got)
+			}
+			if id := msg.Header.Get("Message-Id"); !strings.HasPrefix(id, "<") || !strings.HasSuffix(id, "@"+strings.Split(tt.message.From.Address, "@")[1]+">") {
+				t.Fatalf("unexpected message id %q", id)
+			}
+			if got := msg.Header.Get("Mime-Version"); got != "1.0" {
+				t.Fatalf("unexpected mime version %q", got)
+			}
+
+			got := parsePart(t, msg.Header.Get("Content-Type"), "", "", msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestMessage_BytesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		message *Message
+		want    string
+	}{
+		{
+			name:    "no sender",
+			message: (&Message{}).AddTo(mail.Address{Address: "james@rhodes.com"}),
+			want:    "mime: message has no sender",
+		},
+		{
+			name:    "no recipients",
+			message: NewMessage(mail.Address{Address: "tony@stark.com"}),
+			want:    "mime: message has no recipients",
+		},
+		{
+			name:    "header name injection",
+			message: NewMessage(mail.Address{Address: "tony@stark.com"}).AddTo(mail.Address{Address: "james@rhodes.com"}).SetHeader("X-Foo\r\nBcc", "obadiah@stane.com"),
+			want:    `mime: invalid header name "X-Foo\r\nBcc"`,
+		},
+		{
+			name:    "header name with colon",
+			message: NewMessage(mail.Address{Address: "tony@stark.com"}).AddTo(mail.Address{Address: "james@rhodes.com"}).SetHeader("X-Foo: bar", "baz"),
+			want:    `mime: invalid header name "X-Foo: bar"`,
+		},
+		{
+			name:    "empty header name",
+			message: NewMessage(mail.Address{Address: "tony@stark.com"}).AddTo(mail.Address{Address: "james@rhodes.com"}).SetHeader("", "baz"),
+			want:    "mime: empty header name",
+		},
+		{
+			name:    "builder header",
+			message: NewMessage(mail.Address{Address: "tony@stark.com"}).AddTo(mail.Address{Address: "james@rhodes.com"}).SetHeader("message-id", "<1@stark.com>"),
+			want:    `mime: header "Message-Id" is set by the message builder`,
+		},
+		{
+			name: "builder header in map",
+			message: &Message{
+				From:    &mail.Address{Address: "tony@stark.com"},
+				To:      []mail.Address{{Address: "james@rhodes.com"}},
+				Headers: map[string]string{"MIME-Version": "2.0"},
+			},
+			want: `mime: header "MIME-Version" is set by the message builder`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.message.Bytes()
+			if err == nil || err.Error() != tt.want {
+				t.Fatalf("unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestMessage_HeaderInjection(t *testing.T) {
+	raw, err := NewMessage(mail.Address{Address: "tony@stark.com"}).
+		AddTo(mail.Address{Address: "james@rhodes.com"}).
+		SetSubject("Hi\r\nBcc: obadiah@stane.com").
+		SetHeader("x-mark", "42\r\nX-Evil: 1").
+		Bytes()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := msg.Header.Get("Bcc"); got != "" {
+		t.Fatalf("unexpected Bcc header %q", got)
+	}
+	if got := msg.Header.Get("X-Evil"); got != "" {
+		t.Fatalf("unexpected X-Evil header %q", got)
+	}
+	if got, _ := new(stdmime.WordDecoder).DecodeHeader(msg.Header.Get("X-Mark")); got != "42\r\nX-Evil: 1" {
+		t.Fatalf("unexpected X-Mark header %q", got)
+	}
+}
+
+func TestMessage_BccIsNotWritten(t *testing.T) {
+	m := NewMessage(mail.Address{Address: "tony@stark.com"}).
+		AddTo(mail.Address{Address: "james@rhodes.com"}).
+		AddBcc(mail.Address{Address: "pepper@stark.com"}).
+		SetText("Suit is ready.")
+
+	raw, err := m.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := msg.Header["Bcc"]; ok {
+		t.Fatalf("unexpected Bcc header %q", msg.Header.Get("Bcc"))
+	}
+	if bytes.Contains(raw, []byte("pepper@stark.com")) {
+		t.Fatalf("blind copied address leaked into the message")
+	}
+	if diff := cmp.Diff([]mail.Address{{Address: "pepper@stark.com"}}, m.Bcc); diff != "" {
+		t.Fatalf("values are not the same %s", diff)
+	}
+
+	// A message with only blind copied recipients is still valid.
+	if _, err := NewMessage(mail.Address{Address: "tony@stark.com"}).AddBcc(mail.Address{Address: "pepper@stark.com"}).Bytes(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func parsePart(t *testing.T, contentType, disposition, contentId, encoding string, body io.Reader) parsedPart {
+	t.Helper()
+
+	mediaType, params, err := stdmime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p := parsedPart{ContentType: mediaType, ContentId: contentId}
+
+	if disposition != "" {
+		d, dparams, err := stdmime.ParseMediaType(disposition)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		p.Disposition = d
+		p.Filename = dparams["filename"]
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			p.Parts = append(p.Parts, parsePart(t,
+				part.Header.Get("Content-Type"),
+				part.Header.Get("Content-Disposition"),
+				part.Header.Get("Content-Id"),
+				part.Header.Get("Content-Transfer-Encoding"),
+				part,
+			))
+		}
+		return p
+	}
+
+	delete(params, "boundary")
+	if len(params) > 0 {
+		p.Params = params
+	}
+
+	switch encoding {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+
+	b, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.Body = string(b)
+
+	return p
+}
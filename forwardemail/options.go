@@ -0,0 +1,109 @@
+package forwardemail
+
+import (
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+const modulePath = "github.com/abagayev/go-forwardemail"
+
+// Version is the version of this module as recorded in the build info of the
+// program, or "devel" when it is not available.
+var Version = moduleVersion()
+
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+
+	if info.Main.Path == modulePath && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			return dep.Version
+		}
+	}
+
+	return "devel"
+}
+
+func defaultUserAgent() string {
+	return "go-forwardemail/" + Version
+}
+
+// Option configures a Client. Options are applied in order after
+// ClientOptions.
+type Option func(*Client)
+
+// WithHTTPClient uses hc for every request instead of http.DefaultClient.
+// Nil keeps http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc == nil {
+			hc = http.DefaultClient
+		}
+		c.HttpClient = hc
+	}
+}
+
+// WithTimeout limits the time of each attempt, including reading the body.
+// The HTTP client is copied, so a shared one is never modified.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		hc := c.copyHTTPClient()
+		hc.Timeout = d
+		c.HttpClient = hc
+	}
+}
+
+// WithTransport sends requests through rt, for example to configure a proxy
+// or TLS. The HTTP client is copied, so a shared one is never modified.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		hc := c.copyHTTPClient()
+		hc.Transport = rt
+		c.HttpClient = hc
+	}
+}
+
+// copyHTTPClient returns a copy of the HTTP client, treating nil as
+// http.DefaultClient.
+func (c *Client) copyHTTPClient() *http.Client {
+	if c.HttpClient == nil {
+		hc := *http.DefaultClient
+		return &hc
+	}
+
+	hc := *c.HttpClient
+	return &hc
+}
+
+// WithUserAgent replaces the default "go-forwardemail/<version>" user agent.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.UserAgent = ua
+	}
+}
+
+// WithBaseURL points the client at another API server.
+func WithBaseURL(u string) Option {
+	return func(c *Client) {
+		c.ApiUrl = strings.TrimSuffix(u, "/")
+	}
+}
+
+// WithHeader adds a header to every request. A User-Agent set this way takes
+// precedence over WithUserAgent.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		if c.Header == nil {
+			c.Header = http.Header{}
+		}
+		c.Header.Add(key, value)
+	}
+}
@@ -0,0 +1,140 @@
+package forwardemail
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type countingTransport struct {
+	calls int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewClient_Options(t *testing.T) {
+	shared := &http.Client{}
+
+	c := NewClient(ClientOptions{ApiUrl: "https://google.com"},
+		WithHTTPClient(shared),
+		WithTimeout(5*time.Second),
+		WithBaseURL("https://api.stark.com/"),
+	)
+
+	if c.ApiUrl != "https://api.stark.com" {
+		t.Fatalf("unexpected api url %s", c.ApiUrl)
+	}
+	if c.HttpClient == shared || c.HttpClient.Timeout != 5*time.Second {
+		t.Fatalf("expected a copy of the http client with a timeout")
+	}
+	if shared.Timeout != 0 {
+		t.Fatalf("shared http client was modified")
+	}
+
+	c = NewClient(ClientOptions{}, WithTimeout(time.Second), WithTransport(&countingTransport{}))
+	if c.HttpClient == http.DefaultClient || http.DefaultClient.Timeout != 0 || http.DefaultClient.Transport != nil {
+		t.Fatalf("default http client was modified")
+	}
+
+	c = NewClient(ClientOptions{}, WithHTTPClient(shared))
+	if c.HttpClient != shared {
+		t.Fatalf("expected the given http client")
+	}
+
+	c = NewClient(ClientOptions{}, WithHTTPClient(nil), WithTimeout(time.Second))
+	if c.HttpClient == nil || c.HttpClient == http.DefaultClient || c.HttpClient.Timeout != time.Second {
+		t.Fatalf("expected a copy of the default http client")
+	}
+
+	c = NewClient(ClientOptions{}, WithHTTPClient(nil))
+	if c.HttpClient != http.DefaultClient {
+		t.Fatalf("expected the default http client")
+	}
+
+	c = NewClient(ClientOptions{})
+	c.HttpClient = nil
+	WithTransport(&countingTransport{})(c)
+	if c.HttpClient == nil || http.DefaultClient.Transport != nil {
+		t.Fatalf("expected a copy of the default http client")
+	}
+}
+
+func TestClient_RequestHeaders(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []Option
+		wantUserAgent string
+		wantHeader    http.Header
+	}{
+		{
+			name:          "default",
+			wantUserAgent: "go-forwardemail/" + Version,
+			wantHeader:    http.Header{},
+		},
+		{
+			name: "custom",
+			opts: []Option{
+				WithUserAgent("stark-provisioner/1.0"),
+				WithHeader("X-Trace-Id", "abc"),
+				WithHeader("X-Team", "armor"),
+				WithHeader("X-Team", "lab"),
+			},
+			wantUserAgent: "stark-provisioner/1.0",
+			wantHeader: http.Header{
+				"X-Trace-Id": {"abc"},
+				"X-Team":     {"armor", "lab"},
+			},
+		},
+		{
+			name: "user agent header",
+			opts: []Option{
+				WithHeader("User-Agent", "jarvis/2.0"),
+			},
+			wantUserAgent: "jarvis/2.0",
+			wantHeader:    http.Header{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUserAgent string
+			gotHeader := http.Header{}
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotUserAgent = r.UserAgent()
+				for _, k := range []string{"X-Trace-Id", "X-Team"} {
+					if v := r.Header.Values(k); len(v) > 0 {
+						gotHeader[k] = v
+					}
+				}
+				fmt.Fprintf(w, `{}`)
+			}))
+			defer svr.Close()
+
+			transport := &countingTransport{}
+			opts := append([]Option{WithBaseURL(svr.URL), WithTransport(transport)}, tt.opts...)
+
+			c := NewClient(ClientOptions{}, opts...)
+
+			if _, err := c.GetAccount(); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if gotUserAgent != tt.wantUserAgent {
+				t.Fatalf("unexpected user agent %s", gotUserAgent)
+			}
+			if diff := cmp.Diff(tt.wantHeader, gotHeader); diff != "" {
+				t.Fatalf("headers are not the same %s", diff)
+			}
+			if transport.calls != 1 {
+				t.Fatalf("unexpected number of transport calls %d", transport.calls)
+			}
+		})
+	}
+}
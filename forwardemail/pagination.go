@@ -0,0 +1,144 @@
+package forwardemail
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Page is one page of a list endpoint. ItemCount and PageCount are totals
+// across all pages, as reported by the X-Item-Count and X-Page-Count
+// headers.
+type Page[T any] struct {
+	Items     []T
+	Page      int
+	PageCount int
+	ItemCount int
+
+	// NextPage is the number of the following page, or 0 on the last one.
+	NextPage int
+}
+
+// HasNext reports whether there is a page after this one.
+func (p *Page[T]) HasNext() bool {
+	return p.NextPage > 0
+}
+
+func encodePagination(params url.Values, page, limit int) {
+	if page > 0 {
+		params.Set("page", strconv.Itoa(page))
+	}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+}
+
+func getPage[T any](ctx context.Context, c *Client, path string, query url.Values) (*Page[T], error) {
+	req, err := c.newRequest(ctx, "GET", path)
+	if err != nil {
+		return nil, err
+	}
+
+	req.URL.RawQuery = query.Encode()
+
+	res, err := c.doStreamRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	page := Page[T]{}
+
+	err = json.Unmarshal(body, &page.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	page.Page = 1
+	if p, err := strconv.Atoi(query.Get("page")); err == nil && p > 0 {
+		page.Page = p
+	}
+	if p, err := strconv.Atoi(res.Header.Get("X-Page-Current")); err == nil && p > 0 {
+		page.Page = p
+	}
+
+	page.PageCount, _ = strconv.Atoi(res.Header.Get("X-Page-Count"))
+	page.ItemCount, _ = strconv.Atoi(res.Header.Get("X-Item-Count"))
+	page.NextPage = nextPage(res.Header, page.Page, page.PageCount)
+
+	return &page, nil
+}
+
+// nextPage prefers the rel="next" entry of the Link header and falls back to
+// the page count when the header is missing.
+func nextPage(header http.Header, page, pageCount int) int {
+	links := header.Values("Link")
+	if len(links) == 0 {
+		if page < pageCount {
+			return page + 1
+		}
+		return 0
+	}
+
+	for _, link := range links {
+		for _, part := range strings.Split(link, ",") {
+			target, params, ok := strings.Cut(part, ";")
+			if !ok || !strings.Contains(strings.ReplaceAll(params, " ", ""), `rel="next"`) {
+				continue
+			}
+
+			u, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+			if err != nil {
+				continue
+			}
+
+			if next, err := strconv.Atoi(u.Query().Get("page")); err == nil && next > page {
+				return next
+			}
+		}
+	}
+
+	return 0
+}
+
+// all iterates over every item from the start page onwards, fetching pages
+// only as the caller asks for more. It stops after the first error.
+func all[T any](start int, fetch func(page int) (*Page[T], error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		page := start
+		if page < 1 {
+			page = 1
+		}
+
+		for {
+			p, err := fetch(page)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range p.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if !p.HasNext() {
+				return
+			}
+			page = p.NextPage
+		}
+	}
+}
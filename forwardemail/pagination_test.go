@@ -0,0 +1,65 @@
+package forwardemail
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNextPage(t *testing.T) {
+	tests := []struct {
+		name      string
+		link      []string
+		page      int
+		pageCount int
+		want      int
+	}{
+		{
+			name: "single page",
+			page: 1,
+		},
+		{
+			name:      "page count",
+			page:      2,
+			pageCount: 3,
+			want:      3,
+		},
+		{
+			name:      "last page by count",
+			page:      3,
+			pageCount: 3,
+		},
+		{
+			name:      "link",
+			link:      []string{`<https://api.forwardemail.net/v1/domains?page=1&limit=2>; rel="prev", <https://api.forwardemail.net/v1/domains?page=3&limit=2>; rel="next"`},
+			page:      2,
+			pageCount: 3,
+			want:      3,
+		},
+		{
+			name:      "link in several headers",
+			link:      []string{`<https://api.forwardemail.net/v1/domains?page=1>; rel="first"`, `<https://api.forwardemail.net/v1/domains?page=5>; rel = "next"`},
+			page:      4,
+			pageCount: 9,
+			want:      5,
+		},
+		{
+			name:      "link without next",
+			link:      []string{`<https://api.forwardemail.net/v1/domains?page=2>; rel="prev"`},
+			page:      3,
+			pageCount: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for _, l := range tt.link {
+				header.Add("Link", l)
+			}
+
+			if got := nextPage(header, tt.page, tt.pageCount); got != tt.want {
+				t.Fatalf("unexpected next page %d", got)
+			}
+		})
+	}
+}
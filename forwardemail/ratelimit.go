@@ -0,0 +1,118 @@
+package forwardemail
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit is the rate limit state reported by the most recent response.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// RateLimit returns the rate limit state of the latest response that carried
+// X-RateLimit headers, and false when no such response has been seen yet.
+func (c *Client) RateLimit() (RateLimit, bool) {
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+
+	if c.rateLimit == nil {
+		return RateLimit{}, false
+	}
+
+	return *c.rateLimit, true
+}
+
+func (c *Client) recordRateLimit(header http.Header) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+
+	rl := RateLimit{Limit: limit}
+	rl.Remaining, _ = strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+
+	// The reset is a Unix timestamp, but tolerate a number of seconds from
+	// now as well.
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		if reset < 1e9 {
+			rl.Reset = time.Now().Add(time.Duration(reset) * time.Second)
+		} else {
+			rl.Reset = time.Unix(reset, 0)
+		}
+	}
+
+	c.rateLimitMu.Lock()
+	c.rateLimit = &rl
+	c.rateLimitMu.Unlock()
+}
+
+// Limiter delays requests before they are sent. It is shared by every
+// goroutine using the Client, so it must be safe for concurrent use.
+// *rate.Limiter from golang.org/x/time/rate satisfies it.
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// TokenBucket is a Limiter that allows bursts of up to burst requests and
+// then one request per interval.
+type TokenBucket struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    int
+	tokens   float64
+	last     time.Time
+}
+
+// NewTokenBucket returns a full bucket. For a budget of 1000 requests per
+// hour use NewTokenBucket(time.Hour/1000, burst).
+func NewTokenBucket(interval time.Duration, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &TokenBucket{
+		interval: interval,
+		burst:    burst,
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}
+}
+
+// Wait takes a token, blocking until one is available or ctx is done.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	b.mu.Lock()
+
+	now := time.Now()
+	if b.interval > 0 {
+		b.tokens = min(b.tokens+float64(now.Sub(b.last))/float64(b.interval), float64(b.burst))
+	} else {
+		b.tokens = float64(b.burst)
+	}
+	b.last = now
+
+	// Reserve the token up front so concurrent callers queue up behind each
+	// other instead of all waking at the same moment.
+	b.tokens--
+	wait := time.Duration(-b.tokens * float64(b.interval))
+
+	b.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	if err := sleep(ctx, wait); err != nil {
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package forwardemail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient_RateLimit(t *testing.T) {
+	var mu sync.Mutex
+	remaining := 100
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		remaining--
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(remaining))
+		w.Header().Set("X-RateLimit-Reset", "1735689600")
+		mu.Unlock()
+
+		fmt.Fprintf(w, `{"name": "stark.com"}`)
+	}))
+	defer svr.Close()
+
+	c := NewClient(ClientOptions{
+		ApiUrl: svr.URL,
+	})
+
+	if _, ok := c.RateLimit(); ok {
+		t.Fatalf("expected no rate limit before the first request")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = c.GetDomain("stark.com")
+			_, _ = c.RateLimit()
+		}()
+	}
+	wg.Wait()
+
+	got, ok := c.RateLimit()
+	if !ok {
+		t.Fatalf("expected a rate limit")
+	}
+	if got.Limit != 100 || got.Remaining < 90 || got.Remaining > 99 || !got.Reset.Equal(time.Unix(1735689600, 0)) {
+		t.Fatalf("unexpected rate limit %+v", got)
+	}
+}
+
+func TestClient_recordRateLimit(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		want   *RateLimit
+	}{
+		{
+			name:   "no headers",
+			header: http.Header{},
+		},
+		{
+			name: "unix reset",
+			header: http.Header{
+				"X-Ratelimit-Limit":     {"1000"},
+				"X-Ratelimit-Remaining": {"0"},
+				"X-Ratelimit-Reset":     {"1735689600"},
+			},
+			want: &RateLimit{Limit: 1000, Remaining: 0, Reset: time.Unix(1735689600, 0)},
+		},
+		{
+			name: "without reset",
+			header: http.Header{
+				"X-Ratelimit-Limit":     {"1000"},
+				"X-Ratelimit-Remaining": {"999"},
+			},
+			want: &RateLimit{Limit: 1000, Remaining: 999},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(ClientOptions{})
+			c.recordRateLimit(tt.header)
+
+			if diff := cmp.Diff(tt.want, c.rateLimit); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+
+	c := NewClient(ClientOptions{})
+	c.recordRateLimit(http.Header{"X-Ratelimit-Limit": {"10"}, "X-Ratelimit-Reset": {"30"}})
+	if d := time.Until(c.rateLimit.Reset); d < 29*time.Second || d > 30*time.Second {
+		t.Fatalf("unexpected relative reset %s", d)
+	}
+}
+
+type countingLimiter struct {
+	calls int
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	l.calls++
+	return nil
+}
+
+func TestClient_Limiter(t *testing.T) {
+	var attempts int
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, `{"name": "stark.com"}`)
+	}))
+	defer svr.Close()
+
+	limiter := &countingLimiter{}
+
+	c := NewClient(ClientOptions{
+		ApiUrl:      svr.URL,
+		RetryPolicy: &RetryPolicy{MaxAttempts: 2},
+		Limiter:     limiter,
+	})
+
+	if _, err := c.GetDomain("stark.com"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if limiter.calls != 2 {
+		t.Fatalf("unexpected number of limiter calls %d", limiter.calls)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	b := NewTokenBucket(50*time.Millisecond, 2)
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := b.Wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 25*time.Millisecond {
+		t.Fatalf("burst was delayed by %s", elapsed)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = b.Wait(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("two more tokens took only %s", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := b.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package forwardemail
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how transient failures are retried. Only idempotent
+// requests are retried on 502, 503, 504 and connection errors, since the
+// server may already have acted on them. Every request is retried on 429,
+// which the server rejects before doing any work.
+type RetryPolicy struct {
+	// MaxAttempts includes the first attempt, so 1 or less disables retries.
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Jitter is the fraction of each backoff, between 0 and 1, that is
+	// randomized to spread out retries from concurrent clients.
+	Jitter float64
+	// RetryNonIdempotent retries POST and PATCH requests like idempotent
+	// ones. Only enable it when duplicate side effects are acceptable.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a reasonable starting point for
+// ClientOptions.RetryPolicy. Each call returns a new policy, so changing one
+// never affects other clients.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 4,
+		BaseBackoff: 500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+func (p *RetryPolicy) shouldRetry(req *http.Request, res *http.Response, err error) bool {
+	if err != nil && req.Context().Err() != nil {
+		return false
+	}
+
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if res != nil && res.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	if !p.RetryNonIdempotent && (req.Method == http.MethodPost || req.Method == http.MethodPatch) {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	switch res.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// backoff returns the delay before the given retry, starting at 1. A
+// Retry-After header takes precedence but is still capped by MaxBackoff.
+func (p *RetryPolicy) backoff(retry int, res *http.Response) time.Duration {
+	if res != nil {
+		if d, ok := retryAfter(res.Header.Get("Retry-After")); ok {
+			if p.MaxBackoff > 0 && d > p.MaxBackoff {
+				d = p.MaxBackoff
+			}
+			return d
+		}
+	}
+
+	d := p.BaseBackoff
+	for i := 1; i < retry && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+
+	if p.Jitter > 0 && d > 0 {
+		spread := time.Duration(float64(d) * min(p.Jitter, 1))
+		d = d - spread + time.Duration(rand.Int64N(int64(2*spread)+1))
+	}
+
+	return d
+}
+
+// retryAfter parses both forms of the header: a number of seconds and an
+// HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0), true
+	}
+
+	return 0, false
+}
+
+// rewind prepares the request for another attempt by rebuilding its body.
+// shouldRetry already rejected bodies without GetBody.
+func rewind(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+
+	return nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,247 @@
+package forwardemail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient_Retry(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond}
+
+	tests := []struct {
+		name         string
+		policy       *RetryPolicy
+		call         func(c *Client) error
+		statuses     []int
+		wantAttempts int
+		wantErr      error
+	}{
+		{
+			name:   "no policy",
+			policy: nil,
+			call: func(c *Client) error {
+				_, err := c.GetDomain("stark.com")
+				return err
+			},
+			statuses:     []int{503},
+			wantAttempts: 1,
+			wantErr:      &APIError{StatusCode: 503, Body: []byte("status 503")},
+		},
+		{
+			name:   "get recovers",
+			policy: policy,
+			call: func(c *Client) error {
+				_, err := c.GetDomain("stark.com")
+				return err
+			},
+			statuses:     []int{503, 502, 200},
+			wantAttempts: 3,
+		},
+		{
+			name:   "attempts run out",
+			policy: policy,
+			call: func(c *Client) error {
+				return c.DeleteDomain("stark.com")
+			},
+			statuses:     []int{504, 504, 504, 200},
+			wantAttempts: 3,
+			wantErr:      &APIError{StatusCode: 504, Body: []byte("status 504")},
+		},
+		{
+			name:   "client errors are final",
+			policy: policy,
+			call: func(c *Client) error {
+				_, err := c.GetDomain("stark.com")
+				return err
+			},
+			statuses:     []int{404, 200},
+			wantAttempts: 1,
+			wantErr:      &APIError{StatusCode: 404, Body: []byte("status 404")},
+		},
+		{
+			name:   "post is not replayed",
+			policy: policy,
+			call: func(c *Client) error {
+				_, err := c.CreateAlias("stark.com", "tony", AliasParameters{Recipients: pointSliceOfStrings([]string{"tony@stark.com"})})
+				return err
+			},
+			statuses:     []int{502, 200},
+			wantAttempts: 1,
+			wantErr:      &APIError{StatusCode: 502, Body: []byte("status 502")},
+		},
+		{
+			name:   "post is retried on rate limit",
+			policy: policy,
+			call: func(c *Client) error {
+				_, err := c.CreateAlias("stark.com", "tony", AliasParameters{Recipients: pointSliceOfStrings([]string{"tony@stark.com"})})
+				return err
+			},
+			statuses:     []int{429, 200},
+			wantAttempts: 2,
+		},
+		{
+			name:   "post is retried when allowed",
+			policy: &RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond, RetryNonIdempotent: true},
+			call: func(c *Client) error {
+				_, err := c.CreateAlias("stark.com", "tony", AliasParameters{Recipients: pointSliceOfStrings([]string{"tony@stark.com"})})
+				return err
+			},
+			statuses:     []int{502, 200},
+			wantAttempts: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int
+			var bodies []string
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				bodies = append(bodies, r.PostForm.Encode())
+
+				status := tt.statuses[attempts]
+				attempts++
+
+				if status == http.StatusTooManyRequests {
+					w.Header().Set("Retry-After", "0")
+				}
+				w.WriteHeader(status)
+				if status == http.StatusOK {
+					fmt.Fprintf(w, `{}`)
+					return
+				}
+				fmt.Fprintf(w, "status %d", status)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl:      svr.URL,
+				RetryPolicy: tt.policy,
+			})
+
+			err := tt.call(c)
+			if diff := cmp.Diff(tt.wantErr, err, cmp.Comparer(equateErrorMessage)); diff != "" {
+				t.Fatalf("errors are not the same %s", diff)
+			}
+			if attempts != tt.wantAttempts {
+				t.Fatalf("unexpected number of attempts %d", attempts)
+			}
+			for _, body := range bodies {
+				if body != bodies[0] {
+					t.Fatalf("body changed between attempts %q", bodies)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_RetryConnectionError(t *testing.T) {
+	var attempts int
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		fmt.Fprintf(w, `{"name": "stark.com"}`)
+	}))
+	defer svr.Close()
+
+	c := NewClient(ClientOptions{
+		ApiUrl:      svr.URL,
+		RetryPolicy: &RetryPolicy{MaxAttempts: 2, BaseBackoff: time.Millisecond},
+	})
+
+	got, err := c.GetDomain("stark.com")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got.Name != "stark.com" || attempts != 2 {
+		t.Fatalf("unexpected result %v after %d attempts", got, attempts)
+	}
+}
+
+func TestClient_RetryContext(t *testing.T) {
+	var attempts int
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer svr.Close()
+
+	c := NewClient(ClientOptions{
+		ApiUrl:      svr.URL,
+		RetryPolicy: &RetryPolicy{MaxAttempts: 3},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := c.GetDomainContext(ctx, "stark.com")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("unexpected number of attempts %d", attempts)
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := &RetryPolicy{BaseBackoff: time.Second, MaxBackoff: 10 * time.Second}
+
+	tests := []struct {
+		name       string
+		retry      int
+		retryAfter string
+		want       time.Duration
+	}{
+		{name: "first", retry: 1, want: time.Second},
+		{name: "third", retry: 3, want: 4 * time.Second},
+		{name: "capped", retry: 10, want: 10 * time.Second},
+		{name: "retry after seconds", retry: 1, retryAfter: "3", want: 3 * time.Second},
+		{name: "retry after capped", retry: 1, retryAfter: "120", want: 10 * time.Second},
+		{name: "retry after in the past", retry: 1, retryAfter: "Wed, 21 Oct 2015 07:28:00 GMT", want: 0},
+		{name: "invalid retry after", retry: 2, retryAfter: "soon", want: 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &http.Response{Header: http.Header{}}
+			if tt.retryAfter != "" {
+				res.Header.Set("Retry-After", tt.retryAfter)
+			}
+
+			if got := policy.backoff(tt.retry, res); got != tt.want {
+				t.Fatalf("unexpected backoff %s", got)
+			}
+		})
+	}
+
+	jittered := &RetryPolicy{BaseBackoff: time.Second, Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		if got := jittered.backoff(1, nil); got < 500*time.Millisecond || got > 1500*time.Millisecond {
+			t.Fatalf("backoff %s is outside of the jitter range", got)
+		}
+	}
+}
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	a, b := DefaultRetryPolicy(), DefaultRetryPolicy()
+	a.MaxAttempts = 10
+
+	if b.MaxAttempts != 4 || DefaultRetryPolicy().MaxAttempts != 4 {
+		t.Fatalf("default retry policy is shared")
+	}
+}
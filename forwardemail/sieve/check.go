@@ -0,0 +1,430 @@
+package sieve
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// supportedExtensions are the extensions Forward Email accepts. Those set to
+// false are checked but not evaluated: Evaluate treats the body test as
+// false and leaves encoded characters as written.
+var supportedExtensions = map[string]bool{
+	"body":                       false,
+	"comparator-i;ascii-casemap": true,
+	"comparator-i;octet":         true,
+	"copy":                       true,
+	"encoded-character":          false,
+	"envelope":                   true,
+	"ereject":                    true,
+	"fileinto":                   true,
+	"imap4flags":                 true,
+	"regex":                      true,
+	"reject":                     true,
+	"subaddress":                 true,
+	"vacation":                   true,
+	"variables":                  true,
+}
+
+type argumentType int
+
+const (
+	argumentNone argumentType = iota
+	argumentString
+	argumentStrings
+	argumentNumber
+)
+
+func (t argumentType) String() string {
+	switch t {
+	case argumentString:
+		return "a string"
+	case argumentStrings:
+		return "a string list"
+	case argumentNumber:
+		return "a number"
+	}
+
+	return "nothing"
+}
+
+type tagSpec struct {
+	// group is shared by tags that exclude each other, such as match types.
+	group     string
+	value     argumentType
+	extension string
+}
+
+type spec struct {
+	extension  string
+	tags       map[string]tagSpec
+	positional []argumentType
+	// optional is the number of leading positional arguments that may be
+	// left out.
+	optional int
+	// tests is the number of tests taken: 0, 1, or -1 for a test list.
+	tests int
+	block bool
+}
+
+var (
+	comparatorTags = map[string]tagSpec{
+		"comparator": {group: "comparator", value: argumentString},
+	}
+	matchTags = map[string]tagSpec{
+		"is":       {group: "match"},
+		"contains": {group: "match"},
+		"matches":  {group: "match"},
+		"regex":    {group: "match", extension: "regex"},
+	}
+	addressPartTags = map[string]tagSpec{
+		"all":       {group: "address-part"},
+		"localpart": {group: "address-part"},
+		"domain":    {group: "address-part"},
+		"user":      {group: "address-part", extension: "subaddress"},
+		"detail":    {group: "address-part", extension: "subaddress"},
+	}
+	flagsTags = map[string]tagSpec{
+		"flags": {value: argumentStrings, extension: "imap4flags"},
+	}
+	copyTags = map[string]tagSpec{
+		"copy": {extension: "copy"},
+	}
+)
+
+var commandSpecs = map[string]spec{
+	"require": {positional: []argumentType{argumentStrings}},
+	"if":      {tests: 1, block: true},
+	"elsif":   {tests: 1, block: true},
+	"else":    {block: true},
+	"stop":    {},
+	"keep":    {tags: flagsTags},
+	"discard": {},
+	"redirect": {
+		tags:       copyTags,
+		positional: []argumentType{argumentString},
+	},
+	"fileinto": {
+		extension:  "fileinto",
+		tags:       mergeTags(flagsTags, copyTags),
+		positional: []argumentType{argumentString},
+	},
+	"reject":  {extension: "reject", positional: []argumentType{argumentString}},
+	"ereject": {extension: "ereject", positional: []argumentType{argumentString}},
+	"vacation": {
+		extension: "vacation",
+		tags: map[string]tagSpec{
+			"days":      {value: argumentNumber},
+			"subject":   {value: argumentString},
+			"from":      {value: argumentString},
+			"addresses": {value: argumentStrings},
+			"mime":      {},
+			"handle":    {value: argumentString},
+		},
+		positional: []argumentType{argumentString},
+	},
+	"set": {
+		extension: "variables",
+		tags: map[string]tagSpec{
+			"lower":         {group: "case"},
+			"upper":         {group: "case"},
+			"lowerfirst":    {group: "first"},
+			"upperfirst":    {group: "first"},
+			"quotewildcard": {},
+			"length":        {},
+		},
+		positional: []argumentType{argumentString, argumentString},
+	},
+	"setflag":    {extension: "imap4flags", positional: []argumentType{argumentString, argumentStrings}, optional: 1},
+	"addflag":    {extension: "imap4flags", positional: []argumentType{argumentString, argumentStrings}, optional: 1},
+	"removeflag": {extension: "imap4flags", positional: []argumentType{argumentString, argumentStrings}, optional: 1},
+}
+
+var testSpecs = map[string]spec{
+	"address": {
+		tags:       mergeTags(comparatorTags, matchTags, addressPartTags),
+		positional: []argumentType{argumentStrings, argumentStrings},
+	},
+	"envelope": {
+		extension:  "envelope",
+		tags:       mergeTags(comparatorTags, matchTags, addressPartTags),
+		positional: []argumentType{argumentStrings, argumentStrings},
+	},
+	"header": {
+		tags:       mergeTags(comparatorTags, matchTags),
+		positional: []argumentType{argumentStrings, argumentStrings},
+	},
+	"exists": {positional: []argumentType{argumentStrings}},
+	"body": {
+		extension: "body",
+		tags: mergeTags(comparatorTags, matchTags, map[string]tagSpec{
+			"raw":     {group: "transform"},
+			"content": {group: "transform", value: argumentStrings},
+			"text":    {group: "transform"},
+		}),
+		positional: []argumentType{argumentStrings},
+	},
+	"size": {
+		tags: map[string]tagSpec{
+			"over":  {group: "size"},
+			"under": {group: "size"},
+		},
+		positional: []argumentType{argumentNumber},
+	},
+	"true":  {},
+	"false": {},
+	"not":   {tests: 1},
+	"allof": {tests: -1},
+	"anyof": {tests: -1},
+	"string": {
+		extension:  "variables",
+		tags:       mergeTags(comparatorTags, matchTags),
+		positional: []argumentType{argumentStrings, argumentStrings},
+	},
+	"hasflag": {
+		extension:  "imap4flags",
+		tags:       mergeTags(comparatorTags, matchTags),
+		positional: []argumentType{argumentStrings, argumentStrings},
+		optional:   1,
+	},
+}
+
+func mergeTags(maps ...map[string]tagSpec) map[string]tagSpec {
+	tags := map[string]tagSpec{}
+	for _, m := range maps {
+		for k, v := range m {
+			tags[k] = v
+		}
+	}
+
+	return tags
+}
+
+type checker struct {
+	script      *Script
+	extensions  map[string]bool
+	diagnostics Diagnostics
+}
+
+func (s *Script) check() Diagnostics {
+	c := &checker{script: s, extensions: map[string]bool{}}
+
+	c.commands(s.Commands, true)
+
+	return c.diagnostics
+}
+
+func (c *checker) errorf(pos Position, format string, args ...any) {
+	c.diagnostics = append(c.diagnostics, Diagnostic{Pos: pos, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) commands(commands []*Command, top bool) {
+	requires := top
+	previous := ""
+
+	for _, command := range commands {
+		switch command.Name {
+		case "require":
+			if !requires {
+				c.errorf(command.Pos, "require must come before other commands")
+			}
+		case "elsif", "else":
+			if previous != "if" && previous != "elsif" {
+				c.errorf(command.Pos, "%q must follow \"if\" or \"elsif\"", command.Name)
+			}
+		}
+
+		if command.Name != "require" {
+			requires = false
+		}
+		previous = command.Name
+
+		sp, ok := commandSpecs[command.Name]
+		if !ok {
+			c.errorf(command.Pos, "unknown command %q", command.Name)
+		} else {
+			c.node("command", command.Pos, command.Name, sp, command.Arguments, command.Tests)
+
+			if sp.block && command.Block == nil {
+				c.errorf(command.Pos, "command %q expects a block", command.Name)
+			}
+			if !sp.block && command.Block != nil {
+				c.errorf(command.Pos, "command %q does not take a block", command.Name)
+			}
+		}
+
+		if command.Name == "require" {
+			c.require(command.Arguments)
+		}
+
+		c.commands(command.Block, false)
+	}
+}
+
+func (c *checker) require(arguments []Argument) {
+	for _, argument := range arguments {
+		for _, extension := range argument.Strings {
+			if _, ok := supportedExtensions[extension]; !ok {
+				c.errorf(argument.Pos, "unsupported extension %q", extension)
+				continue
+			}
+			if !c.extensions[extension] {
+				c.extensions[extension] = true
+				c.script.Extensions = append(c.script.Extensions, extension)
+			}
+		}
+	}
+}
+
+func (c *checker) tests(tests []*Test) {
+	for _, test := range tests {
+		sp, ok := testSpecs[test.Name]
+		if !ok {
+			c.errorf(test.Pos, "unknown test %q", test.Name)
+			c.tests(test.Tests)
+			continue
+		}
+
+		c.node("test", test.Pos, test.Name, sp, test.Arguments, test.Tests)
+	}
+}
+
+// node checks the arguments and tests of a command or a test against its
+// spec.
+func (c *checker) node(kind string, pos Position, name string, sp spec, arguments []Argument, tests []*Test) {
+	if sp.extension != "" && !c.extensions[sp.extension] {
+		c.errorf(pos, "%s %q requires the %q extension", kind, name, sp.extension)
+	}
+
+	groups := map[string]string{}
+	seen := map[string]bool{}
+	var positional []Argument
+	var regex, size bool
+
+	for i := 0; i < len(arguments); i++ {
+		argument := arguments[i]
+
+		if argument.Kind != ArgumentTag {
+			positional = append(positional, argument)
+			continue
+		}
+
+		if len(positional) > 0 {
+			c.errorf(argument.Pos, "tag :%s must come before positional arguments", argument.Tag)
+			continue
+		}
+
+		tag, ok := sp.tags[argument.Tag]
+		if !ok {
+			c.errorf(argument.Pos, "unknown tag :%s for %s %q", argument.Tag, kind, name)
+			continue
+		}
+		if tag.extension != "" && !c.extensions[tag.extension] {
+			c.errorf(argument.Pos, "tag :%s requires the %q extension", argument.Tag, tag.extension)
+		}
+
+		switch {
+		case seen[argument.Tag]:
+			c.errorf(argument.Pos, "duplicate tag :%s", argument.Tag)
+		case tag.group != "" && groups[tag.group] != "":
+			c.errorf(argument.Pos, "tag :%s conflicts with :%s", argument.Tag, groups[tag.group])
+		}
+		seen[argument.Tag] = true
+		if tag.group != "" && groups[tag.group] == "" {
+			groups[tag.group] = argument.Tag
+		}
+
+		regex = regex || argument.Tag == "regex"
+		size = size || tag.group == "size"
+
+		if tag.value == argumentNone {
+			continue
+		}
+
+		if i+1 >= len(arguments) || !matchesType(arguments[i+1], tag.value) {
+			c.errorf(argument.Pos, "tag :%s expects %s", argument.Tag, tag.value)
+			continue
+		}
+
+		i++
+
+		if argument.Tag == "comparator" {
+			comparator := arguments[i].Strings[0]
+			if comparator != "i;octet" && comparator != "i;ascii-casemap" {
+				c.errorf(arguments[i].Pos, "unsupported comparator %q", comparator)
+			}
+		}
+	}
+
+	if name == "size" && !size {
+		c.errorf(pos, "test \"size\" requires :over or :under")
+	}
+
+	types := sp.positional
+	if len(positional) < len(types) && len(types)-len(positional) <= sp.optional {
+		types = types[len(types)-len(positional):]
+	}
+
+	if len(positional) != len(types) {
+		c.errorf(pos, "wrong number of arguments for %s %q", kind, name)
+	} else {
+		for i, argument := range positional {
+			if !matchesType(argument, types[i]) {
+				c.errorf(argument.Pos, "argument %d of %s %q must be %s", i+1, kind, name, types[i])
+			}
+		}
+	}
+
+	if regex && len(positional) > 0 {
+		keys := positional[len(positional)-1]
+		for _, key := range keys.Strings {
+			if c.extensions["variables"] && strings.Contains(key, "${") {
+				continue
+			}
+			if _, err := regexp.Compile(key); err != nil {
+				c.errorf(keys.Pos, "invalid regular expression %q", key)
+			}
+		}
+	}
+
+	if name == "set" && len(positional) == 2 && !isVariableName(positional[0].Strings[0]) {
+		c.errorf(positional[0].Pos, "invalid variable name %q", positional[0].Strings[0])
+	}
+
+	switch {
+	case sp.tests == 0 && len(tests) > 0:
+		c.errorf(pos, "%s %q does not take a test", kind, name)
+	case sp.tests == 1 && len(tests) != 1:
+		c.errorf(pos, "%s %q expects a single test", kind, name)
+	case sp.tests == -1 && len(tests) == 0:
+		c.errorf(pos, "%s %q expects a test list", kind, name)
+	}
+
+	c.tests(tests)
+}
+
+func matchesType(argument Argument, t argumentType) bool {
+	switch t {
+	case argumentString:
+		return argument.Kind == ArgumentStrings && len(argument.Strings) == 1
+	case argumentStrings:
+		return argument.Kind == ArgumentStrings
+	case argumentNumber:
+		return argument.Kind == ArgumentNumber
+	}
+
+	return false
+}
+
+func isVariableName(name string) bool {
+	if name == "" || !isIdentifierStart(name[0]) {
+		return false
+	}
+	for i := 1; i < len(name); i++ {
+		if !isIdentifierStart(name[i]) && !isDigit(name[i]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,110 @@
+package sieve
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse_Diagnostics(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want Diagnostics
+	}{
+		{
+			name: "valid",
+			src: `require ["fileinto", "imap4flags", "variables", "envelope", "regex", "vacation"];
+if envelope :domain :is "from" "stark.com" { setflag "\\Flagged"; }
+if header :regex "subject" "^\\[(ticket|bug)-[0-9]+\\]" { fileinto :flags "\\Seen" "Tickets"; }
+if string :matches "${1}" "*" { set :lower "kind" "${1}"; }
+if hasflag "\\Flagged" { vacation :subject "Away" :addresses ["tony@stark.com"] "Back soon"; }
+addflag "seen" "\\Seen";`,
+		},
+		{
+			name: "standard extensions",
+			src: `require ["reject", "ereject", "fileinto", "copy", "body", "subaddress", "envelope", "encoded-character"];
+if body :content "text" :contains "suit" { reject "No."; }
+if address :detail "to" "armor" { fileinto :copy "Armor"; }
+if envelope :user "from" "james" { redirect :copy "friday@stark.com"; }
+ereject "Go away.";`,
+		},
+		{
+			name: "copy without require",
+			src:  "require \"fileinto\";\nfileinto :copy \"Armor\";\nif address :detail \"to\" \"armor\" { keep; }",
+			want: Diagnostics{
+				{Pos: Position{Line: 2, Column: 10}, Message: `tag :copy requires the "copy" extension`},
+				{Pos: Position{Line: 3, Column: 12}, Message: `tag :detail requires the "subaddress" extension`},
+			},
+		},
+		{
+			name: "unknown and unsupported",
+			src:  "require [\"fileinto\", \"notify\"];\nfilein \"Archive\";\nif headr \"to\" \"tony\" { keep; }",
+			want: Diagnostics{
+				{Pos: Position{Line: 1, Column: 9}, Message: `unsupported extension "notify"`},
+				{Pos: Position{Line: 2, Column: 1}, Message: `unknown command "filein"`},
+				{Pos: Position{Line: 3, Column: 4}, Message: `unknown test "headr"`},
+			},
+		},
+		{
+			name: "missing require",
+			src:  "fileinto \"Archive\";\nif header :regex \"subject\" \"x\" { keep :flags \"\\\\Seen\"; }",
+			want: Diagnostics{
+				{Pos: Position{Line: 1, Column: 1}, Message: `command "fileinto" requires the "fileinto" extension`},
+				{Pos: Position{Line: 2, Column: 11}, Message: `tag :regex requires the "regex" extension`},
+				{Pos: Position{Line: 2, Column: 39}, Message: `tag :flags requires the "imap4flags" extension`},
+			},
+		},
+		{
+			name: "structure",
+			src:  "keep;\nrequire \"fileinto\";\nelse { keep; }\nif true;\nstop { }",
+			want: Diagnostics{
+				{Pos: Position{Line: 2, Column: 1}, Message: "require must come before other commands"},
+				{Pos: Position{Line: 3, Column: 1}, Message: `"else" must follow "if" or "elsif"`},
+				{Pos: Position{Line: 4, Column: 1}, Message: `command "if" expects a block`},
+				{Pos: Position{Line: 5, Column: 1}, Message: `command "stop" does not take a block`},
+			},
+		},
+		{
+			name: "arguments",
+			src: `require ["regex", "variables"];
+if header :is :contains "subject" "x" { keep; }
+if header :is :is "subject" "x" { keep; }
+if header "subject" :is "x" { keep; }
+if address :comparator "i;unicode" "to" "x" { keep; }
+if size 100 { keep; }
+if header :regex "subject" "(" { keep; }
+if exists ["a", "b"] ["c"] { keep; }
+redirect ["tony@stark.com", "pepper@stark.com"];
+set "1st" "x";
+if not (true, false) { keep; }`,
+			want: Diagnostics{
+				{Pos: Position{Line: 2, Column: 15}, Message: "tag :contains conflicts with :is"},
+				{Pos: Position{Line: 3, Column: 15}, Message: "duplicate tag :is"},
+				{Pos: Position{Line: 4, Column: 21}, Message: "tag :is must come before positional arguments"},
+				{Pos: Position{Line: 5, Column: 24}, Message: `unsupported comparator "i;unicode"`},
+				{Pos: Position{Line: 6, Column: 4}, Message: `test "size" requires :over or :under`},
+				{Pos: Position{Line: 7, Column: 28}, Message: `invalid regular expression "("`},
+				{Pos: Position{Line: 8, Column: 4}, Message: `wrong number of arguments for test "exists"`},
+				{Pos: Position{Line: 9, Column: 10}, Message: `argument 1 of command "redirect" must be a string`},
+				{Pos: Position{Line: 10, Column: 5}, Message: `invalid variable name "1st"`},
+				{Pos: Position{Line: 11, Column: 4}, Message: `test "not" expects a single test`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.src)
+
+			var got Diagnostics
+			if err != nil {
+				got = err.(Diagnostics)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
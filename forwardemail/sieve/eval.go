@@ -0,0 +1,543 @@
+package sieve
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	ActionKeep     = "keep"
+	ActionDiscard  = "discard"
+	ActionFileInto = "fileinto"
+	ActionRedirect = "redirect"
+	ActionVacation = "vacation"
+	ActionReject   = "reject"
+	ActionEreject  = "ereject"
+)
+
+// Message is the sample a script is evaluated against.
+type Message struct {
+	// From and To are the envelope sender and recipient, which are only
+	// seen by the envelope test.
+	From   string
+	To     string
+	Header mail.Header
+	Size   int64
+}
+
+// ReadMessage reads an RFC 5322 message and records its size. The envelope
+// is left empty.
+func ReadMessage(r io.Reader) (*Message, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("sieve: read message: %w", err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("sieve: read message: %w", err)
+	}
+
+	return &Message{Header: msg.Header, Size: int64(len(data))}, nil
+}
+
+type Vacation struct {
+	Reason    string
+	Subject   string
+	From      string
+	Addresses []string
+	Days      int64
+	Mime      bool
+	Handle    string
+}
+
+// Action is something a script would do with a message. Pos locates the
+// command that fired it and is zero for the implicit keep.
+type Action struct {
+	Kind     string
+	Pos      Position
+	Implicit bool
+	Mailbox  string
+	Address  string
+	Flags    []string
+	// Reason is the refusal text of reject and ereject.
+	Reason   string
+	Vacation *Vacation
+}
+
+// Evaluate runs the script against the message and returns the actions that
+// would fire, in order. When no command cancels it the implicit keep comes
+// last. The message body is not available, so the body test is always
+// false.
+func (s *Script) Evaluate(m *Message) []Action {
+	in := &interpreter{
+		message:    m,
+		extensions: map[string]bool{},
+		variables:  map[string]string{},
+	}
+	for _, extension := range s.Extensions {
+		in.extensions[extension] = true
+	}
+
+	in.run(s.Commands)
+
+	if !in.cancelled {
+		in.actions = append(in.actions, Action{Kind: ActionKeep, Implicit: true, Flags: in.currentFlags()})
+	}
+
+	return in.actions
+}
+
+type interpreter struct {
+	message    *Message
+	extensions map[string]bool
+	variables  map[string]string
+	matches    []string
+	flags      []string
+	actions    []Action
+	cancelled  bool
+	stopped    bool
+}
+
+// arguments splits the arguments of a checked command or test into its tags,
+// with their values, and its positional arguments.
+func arguments(sp spec, list []Argument) (map[string]Argument, []Argument) {
+	tags := map[string]Argument{}
+	var positional []Argument
+
+	for i := 0; i < len(list); i++ {
+		if list[i].Kind != ArgumentTag {
+			positional = append(positional, list[i])
+			continue
+		}
+
+		tag := list[i].Tag
+		tags[tag] = Argument{}
+		if sp.tags[tag].value != argumentNone && i+1 < len(list) {
+			i++
+			tags[tag] = list[i]
+		}
+	}
+
+	return tags, positional
+}
+
+func (in *interpreter) run(commands []*Command) {
+	taken := false
+
+	for _, command := range commands {
+		if in.stopped {
+			return
+		}
+
+		tags, positional := arguments(commandSpecs[command.Name], command.Arguments)
+
+		switch command.Name {
+		case "if":
+			taken = in.test(command.Tests[0])
+			if taken {
+				in.run(command.Block)
+			}
+		case "elsif":
+			if !taken {
+				taken = in.test(command.Tests[0])
+				if taken {
+					in.run(command.Block)
+				}
+			}
+		case "else":
+			if !taken {
+				in.run(command.Block)
+			}
+		case "stop":
+			in.stopped = true
+		case "keep":
+			in.act(Action{Kind: ActionKeep, Pos: command.Pos, Flags: in.actionFlags(tags)}, tags)
+		case "discard":
+			in.act(Action{Kind: ActionDiscard, Pos: command.Pos}, tags)
+		case "redirect":
+			in.act(Action{Kind: ActionRedirect, Pos: command.Pos, Address: in.expand(positional[0].Strings[0])}, tags)
+		case "fileinto":
+			in.act(Action{Kind: ActionFileInto, Pos: command.Pos, Mailbox: in.expand(positional[0].Strings[0]), Flags: in.actionFlags(tags)}, tags)
+		case "reject", "ereject":
+			in.act(Action{Kind: command.Name, Pos: command.Pos, Reason: in.expand(positional[0].Strings[0])}, tags)
+		case "vacation":
+			in.vacation(command.Pos, tags, positional)
+		case "set":
+			in.set(tags, positional)
+		case "setflag", "addflag", "removeflag":
+			in.flag(command.Name, positional)
+		}
+	}
+}
+
+// act records an action. Every explicit action except vacation and those
+// tagged :copy cancels the implicit keep.
+func (in *interpreter) act(action Action, tags map[string]Argument) {
+	in.actions = append(in.actions, action)
+	if _, ok := tags["copy"]; !ok && action.Kind != ActionVacation {
+		in.cancelled = true
+	}
+}
+
+func (in *interpreter) vacation(pos Position, tags map[string]Argument, positional []Argument) {
+	vacation := &Vacation{Reason: in.expand(positional[0].Strings[0]), Days: 7}
+
+	if v, ok := tags["days"]; ok {
+		vacation.Days = v.Number
+	}
+	if v, ok := tags["subject"]; ok {
+		vacation.Subject = in.expand(v.Strings[0])
+	}
+	if v, ok := tags["from"]; ok {
+		vacation.From = in.expand(v.Strings[0])
+	}
+	if v, ok := tags["addresses"]; ok {
+		vacation.Addresses = in.expandAll(v.Strings)
+	}
+	if v, ok := tags["handle"]; ok {
+		vacation.Handle = in.expand(v.Strings[0])
+	}
+	_, vacation.Mime = tags["mime"]
+
+	in.act(Action{Kind: ActionVacation, Pos: pos, Vacation: vacation}, tags)
+}
+
+// set assigns a variable, applying modifiers from the highest precedence
+// down as RFC 5229 requires.
+func (in *interpreter) set(tags map[string]Argument, positional []Argument) {
+	value := in.expand(positional[1].Strings[0])
+
+	if _, ok := tags["lower"]; ok {
+		value = strings.ToLower(value)
+	}
+	if _, ok := tags["upper"]; ok {
+		value = strings.ToUpper(value)
+	}
+	if _, ok := tags["lowerfirst"]; ok && value != "" {
+		r, size := utf8.DecodeRuneInString(value)
+		value = strings.ToLower(string(r)) + value[size:]
+	}
+	if _, ok := tags["upperfirst"]; ok && value != "" {
+		r, size := utf8.DecodeRuneInString(value)
+		value = strings.ToUpper(string(r)) + value[size:]
+	}
+	if _, ok := tags["quotewildcard"]; ok {
+		value = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`).Replace(value)
+	}
+	if _, ok := tags["length"]; ok {
+		value = strconv.Itoa(utf8.RuneCountInString(value))
+	}
+
+	in.variables[strings.ToLower(positional[0].Strings[0])] = value
+}
+
+func (in *interpreter) flag(command string, positional []Argument) {
+	current := in.flags
+	variable := ""
+	if len(positional) == 2 {
+		variable = strings.ToLower(in.expand(positional[0].Strings[0]))
+		current = strings.Fields(in.variables[variable])
+	}
+
+	flags := splitFlags(in.expandAll(positional[len(positional)-1].Strings))
+
+	switch command {
+	case "setflag":
+		current = nil
+		fallthrough
+	case "addflag":
+		for _, flag := range flags {
+			if indexFlag(current, flag) < 0 {
+				current = append(current, flag)
+			}
+		}
+	case "removeflag":
+		for _, flag := range flags {
+			if i := indexFlag(current, flag); i >= 0 {
+				current = append(current[:i:i], current[i+1:]...)
+			}
+		}
+	}
+
+	if variable != "" {
+		in.variables[variable] = strings.Join(current, " ")
+		return
+	}
+
+	in.flags = current
+}
+
+func (in *interpreter) currentFlags() []string {
+	if len(in.flags) == 0 {
+		return nil
+	}
+
+	return append([]string(nil), in.flags...)
+}
+
+// actionFlags returns the flags given to keep or fileinto with :flags, or
+// the current internal flags.
+func (in *interpreter) actionFlags(tags map[string]Argument) []string {
+	if v, ok := tags["flags"]; ok {
+		return splitFlags(in.expandAll(v.Strings))
+	}
+
+	return in.currentFlags()
+}
+
+func (in *interpreter) test(test *Test) bool {
+	tags, positional := arguments(testSpecs[test.Name], test.Arguments)
+
+	switch test.Name {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "not":
+		return !in.test(test.Tests[0])
+	case "allof":
+		for _, t := range test.Tests {
+			if !in.test(t) {
+				return false
+			}
+		}
+		return true
+	case "anyof":
+		for _, t := range test.Tests {
+			if in.test(t) {
+				return true
+			}
+		}
+		return false
+	case "exists":
+		for _, name := range in.expandAll(positional[0].Strings) {
+			if len(in.message.Header[textproto.CanonicalMIMEHeaderKey(name)]) == 0 {
+				return false
+			}
+		}
+		return true
+	case "size":
+		limit := positional[0].Number
+		if _, ok := tags["over"]; ok {
+			return in.message.Size > limit
+		}
+		return in.message.Size < limit
+	case "header":
+		return in.compare(tags, in.headers(positional[0].Strings), positional[1].Strings)
+	case "address":
+		return in.compare(tags, in.addresses(tags, positional[0].Strings), positional[1].Strings)
+	case "envelope":
+		return in.compare(tags, in.envelope(tags, positional[0].Strings), positional[1].Strings)
+	case "string":
+		return in.compare(tags, in.expandAll(positional[0].Strings), positional[1].Strings)
+	case "hasflag":
+		flags := in.flags
+		if len(positional) == 2 {
+			flags = nil
+			for _, name := range in.expandAll(positional[0].Strings) {
+				flags = append(flags, strings.Fields(in.variables[strings.ToLower(name)])...)
+			}
+		}
+		return in.compare(tags, flags, splitFlags(positional[len(positional)-1].Strings))
+	}
+
+	return false
+}
+
+// compare reports whether any value matches any key. A successful :matches
+// or :regex comparison sets the match variables.
+func (in *interpreter) compare(tags map[string]Argument, values, keys []string) bool {
+	comparator := "i;ascii-casemap"
+	if v, ok := tags["comparator"]; ok {
+		comparator = v.Strings[0]
+	}
+
+	matchType := "is"
+	for _, t := range []string{"contains", "matches", "regex"} {
+		if _, ok := tags[t]; ok {
+			matchType = t
+		}
+	}
+
+	keys = in.expandAll(keys)
+
+	for _, value := range values {
+		for _, key := range keys {
+			ok, groups := match(comparator, matchType, value, key)
+			if !ok {
+				continue
+			}
+			if groups != nil {
+				in.matches = groups
+			}
+			return true
+		}
+	}
+
+	return false
+}
+
+func (in *interpreter) headers(names []string) []string {
+	var values []string
+	decoder := new(mime.WordDecoder)
+
+	for _, name := range in.expandAll(names) {
+		for _, value := range in.message.Header[textproto.CanonicalMIMEHeaderKey(name)] {
+			if decoded, err := decoder.DecodeHeader(value); err == nil {
+				value = decoded
+			}
+			values = append(values, strings.TrimSpace(value))
+		}
+	}
+
+	return values
+}
+
+func (in *interpreter) addresses(tags map[string]Argument, names []string) []string {
+	var values []string
+
+	for _, value := range in.headers(names) {
+		list, err := mail.ParseAddressList(value)
+		if err != nil {
+			values = appendAddressPart(values, tags, value)
+			continue
+		}
+		for _, address := range list {
+			values = appendAddressPart(values, tags, address.Address)
+		}
+	}
+
+	return values
+}
+
+func (in *interpreter) envelope(tags map[string]Argument, names []string) []string {
+	var values []string
+
+	for _, name := range in.expandAll(names) {
+		switch strings.ToLower(name) {
+		case "from":
+			values = appendAddressPart(values, tags, in.message.From)
+		case "to":
+			values = appendAddressPart(values, tags, in.message.To)
+		}
+	}
+
+	return values
+}
+
+// appendAddressPart appends the part of the address selected by the tags.
+// An address without a "+" detail has no :detail part and is skipped, as
+// RFC 5233 requires.
+func appendAddressPart(values []string, tags map[string]Argument, address string) []string {
+	at := strings.LastIndexByte(address, '@')
+	local := address
+	if at >= 0 {
+		local = address[:at]
+	}
+	user, detail, found := strings.Cut(local, "+")
+
+	if _, ok := tags["localpart"]; ok {
+		return append(values, local)
+	}
+	if _, ok := tags["domain"]; ok {
+		if at < 0 {
+			return append(values, "")
+		}
+		return append(values, address[at+1:])
+	}
+	if _, ok := tags["user"]; ok {
+		return append(values, user)
+	}
+	if _, ok := tags["detail"]; ok {
+		if !found {
+			return values
+		}
+		return append(values, detail)
+	}
+
+	return append(values, address)
+}
+
+// expand replaces "${name}" references with variables and "${N}" with match
+// variables when the variables extension is required. Unknown variables
+// expand to the empty string and malformed references are left as is.
+func (in *interpreter) expand(s string) string {
+	if !in.extensions["variables"] || !strings.Contains(s, "${") {
+		return s
+	}
+
+	var b strings.Builder
+
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			break
+		}
+
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			break
+		}
+
+		name := s[start+2 : start+end]
+
+		b.WriteString(s[:start])
+
+		switch {
+		case name != "" && strings.Trim(name, "0123456789") == "":
+			if n, err := strconv.Atoi(name); err == nil && n < len(in.matches) {
+				b.WriteString(in.matches[n])
+			}
+		case isVariableName(name):
+			b.WriteString(in.variables[strings.ToLower(name)])
+		default:
+			b.WriteString("${")
+			s = s[start+2:]
+			continue
+		}
+
+		s = s[start+end+1:]
+	}
+
+	b.WriteString(s)
+
+	return b.String()
+}
+
+func (in *interpreter) expandAll(list []string) []string {
+	expanded := make([]string, len(list))
+	for i, s := range list {
+		expanded[i] = in.expand(s)
+	}
+
+	return expanded
+}
+
+// splitFlags turns flag lists, where each string may hold several flags
+// separated by spaces, into single flags.
+func splitFlags(list []string) []string {
+	var flags []string
+	for _, s := range list {
+		flags = append(flags, strings.Fields(s)...)
+	}
+
+	return flags
+}
+
+func indexFlag(flags []string, flag string) int {
+	for i, f := range flags {
+		if strings.EqualFold(f, flag) {
+			return i
+		}
+	}
+
+	return -1
+}
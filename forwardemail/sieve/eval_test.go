@@ -0,0 +1,196 @@
+package sieve
+
+import (
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestScript_Evaluate(t *testing.T) {
+	message := &Message{
+		From: "james@rhodes.com",
+		To:   "tony+armor@stark.com",
+		Header: mail.Header{
+			"From":    {`"James Rhodes" <james@rhodes.com>`},
+			"To":      {"Tony Stark <tony@stark.com>, pepper@stark.com"},
+			"Subject": {"=?utf-8?q?=5BTICKET-42=5D_War_Machine_upgrade?="},
+			"X-Spam":  {"no"},
+		},
+		Size: 2048,
+	}
+
+	tests := []struct {
+		name   string
+		script string
+		want   []Action
+	}{
+		{
+			name:   "implicit keep",
+			script: `if header :is "x-spam" "yes" { discard; }`,
+			want: []Action{
+				{Kind: ActionKeep, Implicit: true},
+			},
+		},
+		{
+			name: "fileinto cancels keep",
+			script: `require "fileinto";
+if address :domain "from" "RHODES.com" { fileinto "Friends"; }`,
+			want: []Action{
+				{Kind: ActionFileInto, Pos: Position{Line: 2, Column: 42}, Mailbox: "Friends"},
+			},
+		},
+		{
+			name: "elsif and stop",
+			script: `if size :over 1M { discard; }
+elsif anyof (exists "x-missing", address :localpart :is "to" "pepper") { redirect "friday@stark.com"; stop; }
+else { discard; }
+keep;`,
+			want: []Action{
+				{Kind: ActionRedirect, Pos: Position{Line: 2, Column: 74}, Address: "friday@stark.com"},
+			},
+		},
+		{
+			name: "vacation keeps",
+			script: `require ["vacation", "envelope"];
+if envelope :matches "to" "*@stark.com" { vacation :days 2 :subject "Away" :mime "I am in Malibu."; }`,
+			want: []Action{
+				{Kind: ActionVacation, Pos: Position{Line: 2, Column: 43}, Vacation: &Vacation{Reason: "I am in Malibu.", Subject: "Away", Days: 2, Mime: true}},
+				{Kind: ActionKeep, Implicit: true},
+			},
+		},
+		{
+			name: "variables and regex",
+			script: `require ["fileinto", "variables", "regex", "envelope"];
+if header :regex "subject" "^\\[([a-z]+)-([0-9]+)\\]" { set :upperfirst "kind" "${1}"; set "id" "${2}"; }
+if envelope :localpart :matches "to" "*+*" { set :length "len" "${2}"; }
+fileinto "${kind}/${id}/${len}/${missing}";`,
+			want: []Action{
+				{Kind: ActionFileInto, Pos: Position{Line: 4, Column: 1}, Mailbox: "TICKET/42/5/"},
+			},
+		},
+		{
+			name: "imap4flags",
+			script: `require ["fileinto", "imap4flags", "variables"];
+setflag "\\Seen \\Flagged";
+addflag "$Work";
+removeflag "\\flagged";
+addflag "custom" ["a", "b"];
+if hasflag :is "custom" "b" { keep; }
+if string :is "${custom}" "a b" { fileinto :flags "\\Answered" "Done"; }`,
+			want: []Action{
+				{Kind: ActionKeep, Pos: Position{Line: 6, Column: 31}, Flags: []string{`\Seen`, "$Work"}},
+				{Kind: ActionFileInto, Pos: Position{Line: 7, Column: 35}, Mailbox: "Done", Flags: []string{`\Answered`}},
+			},
+		},
+		{
+			name: "comparators",
+			script: `if header :contains :comparator "i;octet" "subject" "war machine" { discard; }
+if header :contains "subject" "war machine" { redirect "rhodey@stark.com"; }`,
+			want: []Action{
+				{Kind: ActionRedirect, Pos: Position{Line: 2, Column: 47}, Address: "rhodey@stark.com"},
+			},
+		},
+		{
+			name: "copy keeps",
+			script: `require ["fileinto", "copy"];
+fileinto :copy "Archive";
+redirect :copy "friday@stark.com";`,
+			want: []Action{
+				{Kind: ActionFileInto, Pos: Position{Line: 2, Column: 1}, Mailbox: "Archive"},
+				{Kind: ActionRedirect, Pos: Position{Line: 3, Column: 1}, Address: "friday@stark.com"},
+				{Kind: ActionKeep, Implicit: true},
+			},
+		},
+		{
+			name: "subaddress and reject",
+			script: `require ["envelope", "subaddress", "fileinto", "reject"];
+if envelope :detail "to" "armor" { fileinto "Armor"; }
+if address :detail "from" "" { discard; }
+if envelope :user "to" "tony" { reject "No more suits."; }`,
+			want: []Action{
+				{Kind: ActionFileInto, Pos: Position{Line: 2, Column: 36}, Mailbox: "Armor"},
+				{Kind: ActionReject, Pos: Position{Line: 4, Column: 33}, Reason: "No more suits."},
+			},
+		},
+		{
+			name: "body is not evaluated",
+			script: `require "body";
+if body :text :contains "suit" { discard; }`,
+			want: []Action{
+				{Kind: ActionKeep, Implicit: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script, err := Parse(tt.script)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			got := script.Evaluate(message)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestReadMessage(t *testing.T) {
+	raw := "From: james@rhodes.com\r\nSubject: Suit up\r\n\r\nNow.\r\n"
+
+	got, err := ReadMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	want := &Message{
+		Header: mail.Header{
+			"From":    {"james@rhodes.com"},
+			"Subject": {"Suit up"},
+		},
+		Size: int64(len(raw)),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("values are not the same %s", diff)
+	}
+
+	if _, err := ReadMessage(strings.NewReader("not a message")); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		comparator string
+		matchType  string
+		value      string
+		key        string
+		want       bool
+		wantGroups []string
+	}{
+		{comparator: "i;ascii-casemap", matchType: "is", value: "Tony", key: "TONY", want: true},
+		{comparator: "i;octet", matchType: "is", value: "Tony", key: "TONY"},
+		{comparator: "i;ascii-casemap", matchType: "matches", value: "Re: Iron Man", key: "re: *", want: true, wantGroups: []string{"Re: Iron Man", "Iron Man"}},
+		{comparator: "i;ascii-casemap", matchType: "matches", value: "a+b+c", key: "*+*", want: true, wantGroups: []string{"a+b+c", "a", "b+c"}},
+		{comparator: "i;ascii-casemap", matchType: "matches", value: "what?", key: `*\?`, want: true, wantGroups: []string{"what?", "what"}},
+		{comparator: "i;ascii-casemap", matchType: "matches", value: "why", key: `*\?`},
+		{comparator: "i;octet", matchType: "matches", value: "Jarvis", key: "?arvis", want: true, wantGroups: []string{"Jarvis", "J"}},
+		{comparator: "i;ascii-casemap", matchType: "regex", value: "Mark 42", key: "mark ([0-9]+)", want: true, wantGroups: []string{"Mark 42", "42"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.matchType+" "+tt.key, func(t *testing.T) {
+			got, groups := match(tt.comparator, tt.matchType, tt.value, tt.key)
+			if got != tt.want {
+				t.Fatalf("unexpected result %v", got)
+			}
+			if diff := cmp.Diff(tt.wantGroups, groups); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
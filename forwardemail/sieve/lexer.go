@@ -0,0 +1,323 @@
+// Package sieve parses RFC 5228 Sieve scripts, checks them against the
+// extensions Forward Email supports and evaluates them against a sample
+// message, so filters can be reviewed before they are uploaded.
+//
+// Besides the base language the fileinto, vacation, variables, imap4flags,
+// envelope, regex, reject, ereject, copy and subaddress extensions are
+// understood. The body and encoded-character extensions are checked but not
+// evaluated. Regular expressions use the RE2 syntax of the standard library
+// rather than POSIX.
+package sieve
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Position is a 1-based line and column in a script. Columns count
+// characters, not bytes.
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Diagnostic is a problem found in a script.
+type Diagnostic struct {
+	Pos     Position
+	Message string
+}
+
+func (d Diagnostic) Error() string {
+	return fmt.Sprintf("sieve: %s: %s", d.Pos, d.Message)
+}
+
+// Diagnostics is the error returned by Parse. It lists every problem found,
+// in the order they appear in the script.
+type Diagnostics []Diagnostic
+
+func (d Diagnostics) Error() string {
+	lines := make([]string, len(d))
+	for i := range d {
+		lines[i] = d[i].Error()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenTag
+	tokenNumber
+	tokenString
+	tokenPunct
+)
+
+type token struct {
+	kind   tokenKind
+	pos    Position
+	text   string
+	number int64
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of script"
+	case tokenIdentifier:
+		return fmt.Sprintf("identifier %q", t.text)
+	case tokenTag:
+		return ":" + t.text
+	case tokenNumber:
+		return "number"
+	case tokenString:
+		return "string"
+	}
+
+	return fmt.Sprintf("%q", t.text)
+}
+
+type lexer struct {
+	src  string
+	off  int
+	line int
+	col  int
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: src, line: 1, col: 1}
+}
+
+func (l *lexer) pos() Position {
+	return Position{Line: l.line, Column: l.col}
+}
+
+func (l *lexer) peek(n int) byte {
+	if l.off+n >= len(l.src) {
+		return 0
+	}
+
+	return l.src[l.off+n]
+}
+
+func (l *lexer) advance() {
+	r, size := utf8.DecodeRuneInString(l.src[l.off:])
+	l.off += size
+
+	if r == '\n' {
+		l.line++
+		l.col = 1
+	} else {
+		l.col++
+	}
+}
+
+func (l *lexer) errorf(pos Position, format string, args ...any) error {
+	return Diagnostic{Pos: pos, Message: fmt.Sprintf(format, args...)}
+}
+
+func (l *lexer) next() (token, error) {
+	if err := l.skipSpace(); err != nil {
+		return token{}, err
+	}
+
+	pos := l.pos()
+
+	if l.off >= len(l.src) {
+		return token{kind: tokenEOF, pos: pos}, nil
+	}
+
+	c := l.src[l.off]
+
+	switch {
+	case strings.IndexByte("[](),;{}", c) >= 0:
+		l.advance()
+		return token{kind: tokenPunct, pos: pos, text: string(c)}, nil
+	case c == '"':
+		return l.quoted(pos)
+	case c == ':':
+		l.advance()
+		if !isIdentifierStart(l.peek(0)) {
+			return token{}, l.errorf(pos, "expected tag name after \":\"")
+		}
+		return token{kind: tokenTag, pos: pos, text: l.identifier()}, nil
+	case isDigit(c):
+		return l.number(pos)
+	case isIdentifierStart(c):
+		name := l.identifier()
+		if name == "text" && l.peek(0) == ':' {
+			l.advance()
+			return l.multiline(pos)
+		}
+		return token{kind: tokenIdentifier, pos: pos, text: name}, nil
+	}
+
+	r, _ := utf8.DecodeRuneInString(l.src[l.off:])
+
+	return token{}, l.errorf(pos, "unexpected character %q", r)
+}
+
+func (l *lexer) skipSpace() error {
+	for l.off < len(l.src) {
+		switch c := l.src[l.off]; {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			l.advance()
+		case c == '#':
+			for l.off < len(l.src) && l.src[l.off] != '\n' {
+				l.advance()
+			}
+		case c == '/' && l.peek(1) == '*':
+			pos := l.pos()
+			end := strings.Index(l.src[l.off+2:], "*/")
+			if end < 0 {
+				return l.errorf(pos, "unterminated comment")
+			}
+			for stop := l.off + 2 + end + 2; l.off < stop; {
+				l.advance()
+			}
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// identifier reads an identifier. Identifiers are case-insensitive, so they
+// are returned in lower case.
+func (l *lexer) identifier() string {
+	start := l.off
+	for l.off < len(l.src) && (isIdentifierStart(l.src[l.off]) || isDigit(l.src[l.off])) {
+		l.advance()
+	}
+
+	return strings.ToLower(l.src[start:l.off])
+}
+
+func (l *lexer) number(pos Position) (token, error) {
+	var n int64
+
+	for l.off < len(l.src) && isDigit(l.src[l.off]) {
+		if n > (1<<62)/10 {
+			return token{}, l.errorf(pos, "number is too large")
+		}
+		n = n*10 + int64(l.src[l.off]-'0')
+		l.advance()
+	}
+
+	var shift uint
+	switch l.peek(0) {
+	case 'K', 'k':
+		shift = 10
+	case 'M', 'm':
+		shift = 20
+	case 'G', 'g':
+		shift = 30
+	}
+
+	if shift > 0 {
+		if n > (1<<62)>>shift {
+			return token{}, l.errorf(pos, "number is too large")
+		}
+		n <<= shift
+		l.advance()
+	}
+
+	return token{kind: tokenNumber, pos: pos, number: n}, nil
+}
+
+func (l *lexer) quoted(pos Position) (token, error) {
+	var b strings.Builder
+
+	l.advance()
+
+	for {
+		if l.off >= len(l.src) {
+			return token{}, l.errorf(pos, "unterminated string")
+		}
+
+		switch c := l.src[l.off]; c {
+		case '"':
+			l.advance()
+			return token{kind: tokenString, pos: pos, text: b.String()}, nil
+		case '\\':
+			l.advance()
+			if l.off >= len(l.src) {
+				return token{}, l.errorf(pos, "unterminated string")
+			}
+			fallthrough
+		default:
+			r, _ := utf8.DecodeRuneInString(l.src[l.off:])
+			b.WriteRune(r)
+			l.advance()
+		}
+	}
+}
+
+// multiline reads the body of a "text:" string up to the line holding a
+// single dot. Lines starting with a dot are dot-stuffed.
+func (l *lexer) multiline(pos Position) (token, error) {
+	for l.peek(0) == ' ' || l.peek(0) == '\t' {
+		l.advance()
+	}
+
+	switch {
+	case l.peek(0) == '#':
+		for l.off < len(l.src) && l.src[l.off] != '\n' {
+			l.advance()
+		}
+	case l.peek(0) == '\r' && l.peek(1) == '\n', l.peek(0) == '\n':
+	default:
+		return token{}, l.errorf(l.pos(), "expected line break after \"text:\"")
+	}
+
+	if l.off >= len(l.src) {
+		return token{}, l.errorf(pos, "unterminated multi-line string")
+	}
+
+	for l.src[l.off] != '\n' {
+		l.advance()
+	}
+	l.advance()
+
+	var b strings.Builder
+
+	for l.off < len(l.src) {
+		end := strings.IndexByte(l.src[l.off:], '\n')
+		if end < 0 {
+			end = len(l.src) - l.off
+		}
+
+		line := strings.TrimSuffix(l.src[l.off:l.off+end], "\r")
+		for stop := l.off + end; l.off < stop; {
+			l.advance()
+		}
+		if l.off < len(l.src) {
+			l.advance()
+		}
+
+		if line == "." {
+			return token{kind: tokenString, pos: pos, text: b.String()}, nil
+		}
+
+		b.WriteString(strings.TrimPrefix(line, "."))
+		b.WriteString("\r\n")
+	}
+
+	return token{}, l.errorf(pos, "unterminated multi-line string")
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentifierStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,108 @@
+package sieve
+
+import (
+	"regexp"
+	"strings"
+)
+
+// match compares a value with a key using the comparator and match type of
+// a test. For :matches and :regex it also returns the strings captured by
+// the wildcards or groups, starting with the whole match.
+func match(comparator, matchType, value, key string) (bool, []string) {
+	fold := comparator != "i;octet"
+
+	switch matchType {
+	case "contains":
+		if fold {
+			return strings.Contains(asciiLower(value), asciiLower(key)), nil
+		}
+		return strings.Contains(value, key), nil
+	case "matches":
+		return glob(value, key, fold)
+	case "regex":
+		if fold {
+			key = "(?i)" + key
+		}
+
+		re, err := regexp.Compile(key)
+		if err != nil {
+			return false, nil
+		}
+
+		groups := re.FindStringSubmatch(value)
+
+		return groups != nil, groups
+	}
+
+	if fold {
+		return asciiLower(value) == asciiLower(key), nil
+	}
+
+	return value == key, nil
+}
+
+// glob matches a :matches pattern, where "*" stands for any run of
+// characters, "?" for a single character and a backslash escapes the next
+// one. Wildcards match as little as possible.
+func glob(value, pattern string, fold bool) (bool, []string) {
+	subject := value
+	if fold {
+		// Folding only touches ASCII letters, so offsets found in the folded
+		// value are valid in the original one.
+		subject, pattern = asciiLower(value), asciiLower(pattern)
+	}
+
+	var b strings.Builder
+
+	b.WriteString("(?s)^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '*':
+			b.WriteString("(.*?)")
+		case c == '?':
+			b.WriteString("(.)")
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			fallthrough
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return false, nil
+	}
+
+	return captures(re, value, subject)
+}
+
+func captures(re *regexp.Regexp, value, subject string) (bool, []string) {
+	indexes := re.FindStringSubmatchIndex(subject)
+	if indexes == nil {
+		return false, nil
+	}
+
+	groups := make([]string, len(indexes)/2)
+	for i := range groups {
+		if indexes[2*i] >= 0 {
+			groups[i] = value[indexes[2*i]:indexes[2*i+1]]
+		}
+	}
+
+	return true, groups
+}
+
+func asciiLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+
+	return string(b)
+}
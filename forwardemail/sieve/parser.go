@@ -0,0 +1,281 @@
+package sieve
+
+import (
+	"errors"
+)
+
+type Script struct {
+	// Extensions lists the capabilities named by require commands.
+	Extensions []string
+	Commands   []*Command
+}
+
+type Command struct {
+	Pos       Position
+	Name      string
+	Arguments []Argument
+	Tests     []*Test
+
+	// Block is nil when the command ends with a semicolon and non-nil,
+	// possibly empty, when it is followed by braces.
+	Block []*Command
+}
+
+type Test struct {
+	Pos       Position
+	Name      string
+	Arguments []Argument
+	Tests     []*Test
+}
+
+type ArgumentKind int
+
+const (
+	ArgumentTag ArgumentKind = iota
+	ArgumentNumber
+	ArgumentStrings
+)
+
+// Argument is a tag such as ":contains", a number or a string list. A
+// single string is a list with one element.
+type Argument struct {
+	Pos     Position
+	Kind    ArgumentKind
+	Tag     string
+	Number  int64
+	Strings []string
+}
+
+// Parse parses a script and checks it for unknown commands and tests,
+// misplaced or malformed arguments and extensions that are used without
+// being required. The returned error is always of type Diagnostics.
+func Parse(src string) (*Script, error) {
+	p := &parser{lex: newLexer(src)}
+
+	commands, err := p.parse()
+	if err != nil {
+		var d Diagnostic
+		if errors.As(err, &d) {
+			return nil, Diagnostics{d}
+		}
+		return nil, err
+	}
+
+	s := &Script{Commands: commands}
+
+	if diagnostics := s.check(); len(diagnostics) > 0 {
+		return nil, diagnostics
+	}
+
+	return s, nil
+}
+
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+func (p *parser) next() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+
+	p.tok = tok
+
+	return nil
+}
+
+func (p *parser) is(punct string) bool {
+	return p.tok.kind == tokenPunct && p.tok.text == punct
+}
+
+func (p *parser) unexpected(expected string) error {
+	return p.lex.errorf(p.tok.pos, "expected %s, found %s", expected, p.tok)
+}
+
+func (p *parser) parse() ([]*Command, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	commands, err := p.commands()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind != tokenEOF {
+		return nil, p.unexpected("command")
+	}
+
+	return commands, nil
+}
+
+// commands reads commands up to the end of the script or a closing brace,
+// which is left for the caller.
+func (p *parser) commands() ([]*Command, error) {
+	commands := []*Command{}
+
+	for p.tok.kind == tokenIdentifier {
+		command, err := p.command()
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, command)
+	}
+
+	return commands, nil
+}
+
+func (p *parser) command() (*Command, error) {
+	command := &Command{Pos: p.tok.pos, Name: p.tok.text}
+
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	var err error
+
+	command.Arguments, command.Tests, err = p.arguments()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case p.is(";"):
+		return command, p.next()
+	case p.is("{"):
+		open := p.tok.pos
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		command.Block, err = p.commands()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.is("}") {
+			if p.tok.kind == tokenEOF {
+				return nil, p.lex.errorf(open, "missing \"}\" for block")
+			}
+			return nil, p.unexpected("command or \"}\"")
+		}
+
+		return command, p.next()
+	}
+
+	return nil, p.unexpected("\";\" or \"{\"")
+}
+
+func (p *parser) arguments() ([]Argument, []*Test, error) {
+	var arguments []Argument
+
+	for {
+		argument := Argument{Pos: p.tok.pos}
+
+		switch {
+		case p.tok.kind == tokenTag:
+			argument.Kind, argument.Tag = ArgumentTag, p.tok.text
+		case p.tok.kind == tokenNumber:
+			argument.Kind, argument.Number = ArgumentNumber, p.tok.number
+		case p.tok.kind == tokenString:
+			argument.Kind, argument.Strings = ArgumentStrings, []string{p.tok.text}
+		case p.is("["):
+			list, err := p.stringList()
+			if err != nil {
+				return nil, nil, err
+			}
+			argument.Kind, argument.Strings = ArgumentStrings, list
+		case p.tok.kind == tokenIdentifier:
+			test, err := p.test()
+			if err != nil {
+				return nil, nil, err
+			}
+			return arguments, []*Test{test}, nil
+		case p.is("("):
+			tests, err := p.testList()
+			if err != nil {
+				return nil, nil, err
+			}
+			return arguments, tests, nil
+		default:
+			return arguments, nil, nil
+		}
+
+		arguments = append(arguments, argument)
+
+		if err := p.next(); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// stringList reads a bracketed list, leaving the closing bracket as the
+// current token.
+func (p *parser) stringList() ([]string, error) {
+	var list []string
+
+	for {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokenString {
+			return nil, p.unexpected("string")
+		}
+		list = append(list, p.tok.text)
+
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if p.is("]") {
+			return list, nil
+		}
+		if !p.is(",") {
+			return nil, p.unexpected("\",\" or \"]\"")
+		}
+	}
+}
+
+func (p *parser) test() (*Test, error) {
+	test := &Test{Pos: p.tok.pos, Name: p.tok.text}
+
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	var err error
+
+	test.Arguments, test.Tests, err = p.arguments()
+	if err != nil {
+		return nil, err
+	}
+
+	return test, nil
+}
+
+func (p *parser) testList() ([]*Test, error) {
+	var tests []*Test
+
+	for {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokenIdentifier {
+			return nil, p.unexpected("test")
+		}
+
+		test, err := p.test()
+		if err != nil {
+			return nil, err
+		}
+		tests = append(tests, test)
+
+		if p.is(")") {
+			return tests, p.next()
+		}
+		if !p.is(",") {
+			return nil, p.unexpected("\",\" or \")\"")
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package sieve
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	src := "# Stark Industries filters\r\n" +
+		"require [\"fileinto\", \"vacation\"];\r\n" +
+		"/* large\r\n   attachments */\r\n" +
+		"if anyof (size :over 10M, header :contains \"Subject\" \"\\\"urgent\\\"\") {\r\n" +
+		"  fileinto \"Big\";\r\n" +
+		"} else {\r\n" +
+		"  vacation :days 3 text:\r\n" +
+		"I am in Malibu.\r\n" +
+		"..signature\r\n" +
+		".\r\n" +
+		";\r\n" +
+		"}\r\n"
+
+	want := &Script{
+		Extensions: []string{"fileinto", "vacation"},
+		Commands: []*Command{
+			{
+				Pos:  Position{Line: 2, Column: 1},
+				Name: "require",
+				Arguments: []Argument{
+					{Pos: Position{Line: 2, Column: 9}, Kind: ArgumentStrings, Strings: []string{"fileinto", "vacation"}},
+				},
+			},
+			{
+				Pos:  Position{Line: 5, Column: 1},
+				Name: "if",
+				Tests: []*Test{
+					{
+						Pos:  Position{Line: 5, Column: 4},
+						Name: "anyof",
+						Tests: []*Test{
+							{
+								Pos:  Position{Line: 5, Column: 11},
+								Name: "size",
+								Arguments: []Argument{
+									{Pos: Position{Line: 5, Column: 16}, Kind: ArgumentTag, Tag: "over"},
+									{Pos: Position{Line: 5, Column: 22}, Kind: ArgumentNumber, Number: 10 << 20},
+								},
+							},
+							{
+								Pos:  Position{Line: 5, Column: 27},
+								Name: "header",
+								Arguments: []Argument{
+									{Pos: Position{Line: 5, Column: 34}, Kind: ArgumentTag, Tag: "contains"},
+									{Pos: Position{Line: 5, Column: 44}, Kind: ArgumentStrings, Strings: []string{"Subject"}},
+									{Pos: Position{Line: 5, Column: 54}, Kind: ArgumentStrings, Strings: []string{`"urgent"`}},
+								},
+							},
+						},
+					},
+				},
+				Block: []*Command{
+					{
+						Pos:  Position{Line: 6, Column: 3},
+						Name: "fileinto",
+						Arguments: []Argument{
+							{Pos: Position{Line: 6, Column: 12}, Kind: ArgumentStrings, Strings: []string{"Big"}},
+						},
+					},
+				},
+			},
+			{
+				Pos:  Position{Line: 7, Column: 3},
+				Name: "else",
+				Block: []*Command{
+					{
+						Pos:  Position{Line: 8, Column: 3},
+						Name: "vacation",
+						Arguments: []Argument{
+							{Pos: Position{Line: 8, Column: 12}, Kind: ArgumentTag, Tag: "days"},
+							{Pos: Position{Line: 8, Column: 18}, Kind: ArgumentNumber, Number: 3},
+							{Pos: Position{Line: 8, Column: 20}, Kind: ArgumentStrings, Strings: []string{"I am in Malibu.\r\n.signature\r\n"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got, err := Parse(src)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("values are not the same %s", diff)
+	}
+}
+
+func TestParse_SyntaxErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "missing semicolon",
+			src:  "keep\r\ndiscard",
+			want: `sieve: 2:8: expected ";" or "{", found end of script`,
+		},
+		{
+			name: "unterminated string",
+			src:  "require \"fileinto;\n",
+			want: "sieve: 1:9: unterminated string",
+		},
+		{
+			name: "unterminated comment",
+			src:  "keep; /* forever",
+			want: "sieve: 1:7: unterminated comment",
+		},
+		{
+			name: "unterminated multi-line string",
+			src:  "redirect text:\r\ntony@stark.com\r\n",
+			want: "sieve: 1:10: unterminated multi-line string",
+		},
+		{
+			name: "missing closing brace",
+			src:  "if true {\n  keep;\n",
+			want: `sieve: 1:9: missing "}" for block`,
+		},
+		{
+			name: "unexpected closing brace",
+			src:  "keep;\n}",
+			want: `sieve: 2:1: expected command, found "}"`,
+		},
+		{
+			name: "empty string list",
+			src:  "require [];",
+			want: `sieve: 1:10: expected string, found "]"`,
+		},
+		{
+			name: "incomplete test list",
+			src:  "if anyof (true, ] { keep; }",
+			want: `sieve: 1:17: expected test, found "]"`,
+		},
+		{
+			name: "unexpected character",
+			src:  "keep; ¿",
+			want: `sieve: 1:7: unexpected character '¿'`,
+		},
+		{
+			name: "number too large",
+			src:  "if size :over 99999999999G { keep; }",
+			want: "sieve: 1:15: number is too large",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.src)
+			if err == nil || err.Error() != tt.want {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if _, ok := err.(Diagnostics); !ok {
+				t.Fatalf("unexpected error type %T", err)
+			}
+		})
+	}
+}
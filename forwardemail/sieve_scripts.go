@@ -0,0 +1,207 @@
+package forwardemail
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type SieveScript struct {
+	Name               string    `json:"name"`
+	Description        string    `json:"description"`
+	Content            string    `json:"content"`
+	IsActive           bool      `json:"is_active"`
+	IsValid            bool      `json:"is_valid"`
+	ValidationErrors   []string  `json:"validation_errors"`
+	RequiredExtensions []string  `json:"required_capabilities"`
+	Id                 string    `json:"id"`
+	Object             string    `json:"object"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+type SieveScriptParameters struct {
+	Name        *string
+	Description *string
+	Content     *string
+}
+
+func (c *Client) GetSieveScripts(domain string, alias string) ([]SieveScript, error) {
+	return c.GetSieveScriptsContext(context.Background(), domain, alias)
+}
+
+// GetSieveScriptsContext is like GetSieveScripts but carries ctx with the
+// request.
+func (c *Client) GetSieveScriptsContext(ctx context.Context, domain string, alias string) ([]SieveScript, error) {
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("/v1/domains/%s/aliases/%s/sieve", domain, alias))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []SieveScript
+
+	err = json.Unmarshal(res, &items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (c *Client) GetSieveScript(domain string, alias string, id string) (*SieveScript, error) {
+	return c.GetSieveScriptContext(context.Background(), domain, alias, id)
+}
+
+// GetSieveScriptContext is like GetSieveScript but carries ctx with the
+// request.
+func (c *Client) GetSieveScriptContext(ctx context.Context, domain string, alias string, id string) (*SieveScript, error) {
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("/v1/domains/%s/aliases/%s/sieve/%s", domain, alias, id))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item SieveScript
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) CreateSieveScript(domain string, alias string, parameters SieveScriptParameters) (*SieveScript, error) {
+	return c.CreateSieveScriptContext(context.Background(), domain, alias, parameters)
+}
+
+// CreateSieveScriptContext is like CreateSieveScript but carries ctx with the
+// request.
+func (c *Client) CreateSieveScriptContext(ctx context.Context, domain string, alias string, parameters SieveScriptParameters) (*SieveScript, error) {
+	req, err := c.newRequest(ctx, "POST", fmt.Sprintf("/v1/domains/%s/aliases/%s/sieve", domain, alias))
+	if err != nil {
+		return nil, err
+	}
+
+	setFormBody(req, parameters.encode())
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item SieveScript
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) UpdateSieveScript(domain string, alias string, id string, parameters SieveScriptParameters) (*SieveScript, error) {
+	return c.UpdateSieveScriptContext(context.Background(), domain, alias, id, parameters)
+}
+
+// UpdateSieveScriptContext is like UpdateSieveScript but carries ctx with the
+// request.
+func (c *Client) UpdateSieveScriptContext(ctx context.Context, domain string, alias string, id string, parameters SieveScriptParameters) (*SieveScript, error) {
+	req, err := c.newRequest(ctx, "PUT", fmt.Sprintf("/v1/domains/%s/aliases/%s/sieve/%s", domain, alias, id))
+	if err != nil {
+		return nil, err
+	}
+
+	setFormBody(req, parameters.encode())
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item SieveScript
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// ActivateSieveScript makes the script the one that filters incoming mail for
+// the alias. Only one script can be active at a time, so the server
+// deactivates the previously active script.
+func (c *Client) ActivateSieveScript(domain string, alias string, id string) (*SieveScript, error) {
+	return c.ActivateSieveScriptContext(context.Background(), domain, alias, id)
+}
+
+// ActivateSieveScriptContext is like ActivateSieveScript but carries ctx with
+// the request.
+func (c *Client) ActivateSieveScriptContext(ctx context.Context, domain string, alias string, id string) (*SieveScript, error) {
+	req, err := c.newRequest(ctx, "POST", fmt.Sprintf("/v1/domains/%s/aliases/%s/sieve/%s/activate", domain, alias, id))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var item SieveScript
+
+	err = json.Unmarshal(res, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (c *Client) DeleteSieveScript(domain string, alias string, id string) error {
+	return c.DeleteSieveScriptContext(context.Background(), domain, alias, id)
+}
+
+// DeleteSieveScriptContext is like DeleteSieveScript but carries ctx with the
+// request.
+func (c *Client) DeleteSieveScriptContext(ctx context.Context, domain string, alias string, id string) error {
+	req, err := c.newRequest(ctx, "DELETE", fmt.Sprintf("/v1/domains/%s/aliases/%s/sieve/%s", domain, alias, id))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p SieveScriptParameters) encode() url.Values {
+	params := url.Values{}
+
+	for k, v := range map[string]*string{
+		"name":        p.Name,
+		"description": p.Description,
+		"content":     p.Content,
+	} {
+		if v != nil {
+			params.Add(k, *v)
+		}
+	}
+
+	return params
+}
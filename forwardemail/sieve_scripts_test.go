@@ -0,0 +1,386 @@
+package forwardemail
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient_GetSieveScripts(t *testing.T) {
+	tests := []struct {
+		name     string
+		domain   string
+		alias    string
+		response string
+		wantPath string
+		want     []SieveScript
+	}{
+		{
+			name:     "no data",
+			wantPath: "/v1/domains//aliases//sieve",
+		},
+		{
+			name:   "ok",
+			domain: "stark.com",
+			alias:  "tony",
+			response: `[
+				{
+				  "name": "vacation",
+				  "description": "Out of office",
+				  "content": "require \"vacation\";\r\nvacation \"I am in Malibu.\";\r\n",
+				  "is_active": true,
+				  "is_valid": true,
+				  "validation_errors": [],
+				  "required_capabilities": ["vacation"],
+				  "id": "6531b03e0bde8f333acea001",
+				  "object": "sieve_script",
+				  "created_at": "2023-10-20T10:12:46.588Z",
+				  "updated_at": "2023-10-20T10:12:46.588Z"
+				}
+			]`,
+			wantPath: "/v1/domains/stark.com/aliases/tony/sieve",
+			want: []SieveScript{
+				{
+					Name:               "vacation",
+					Description:        "Out of office",
+					Content:            "require \"vacation\";\r\nvacation \"I am in Malibu.\";\r\n",
+					IsActive:           true,
+					IsValid:            true,
+					ValidationErrors:   []string{},
+					RequiredExtensions: []string{"vacation"},
+					Id:                 "6531b03e0bde8f333acea001",
+					Object:             "sieve_script",
+					CreatedAt:          parseTime("2023-10-20T10:12:46.588Z"),
+					UpdatedAt:          parseTime("2023-10-20T10:12:46.588Z"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got, _ := c.GetSieveScripts(tt.domain, tt.alias)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if gotPath != tt.wantPath {
+				t.Fatalf("unexpected path %s", gotPath)
+			}
+		})
+	}
+}
+
+func TestClient_GetSieveScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		domain   string
+		alias    string
+		id       string
+		response string
+		want     *SieveScript
+	}{
+		{
+			name: "no data",
+		},
+		{
+			name:   "invalid script",
+			domain: "stark.com",
+			alias:  "tony",
+			id:     "6531b03e0bde8f333acea002",
+			response: `{
+				"name": "broken",
+				"content": "fileinto \"Archive\";",
+				"is_valid": false,
+				"validation_errors": ["line 1: fileinto requires the \"fileinto\" extension"],
+				"id": "6531b03e0bde8f333acea002",
+				"object": "sieve_script"
+			}`,
+			want: &SieveScript{
+				Name:             "broken",
+				Content:          `fileinto "Archive";`,
+				ValidationErrors: []string{`line 1: fileinto requires the "fileinto" extension`},
+				Id:               "6531b03e0bde8f333acea002",
+				Object:           "sieve_script",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got, _ := c.GetSieveScript(tt.domain, tt.alias, tt.id)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_CreateSieveScript(t *testing.T) {
+	tests := []struct {
+		name       string
+		domain     string
+		alias      string
+		parameters SieveScriptParameters
+		response   string
+		wantForm   url.Values
+		want       *SieveScript
+	}{
+		{
+			name:     "no data",
+			wantForm: url.Values{},
+		},
+		{
+			name:   "ok",
+			domain: "stark.com",
+			alias:  "tony",
+			parameters: SieveScriptParameters{
+				Name:    pointString("archive"),
+				Content: pointString("require \"fileinto\";\r\nfileinto \"Archive\";\r\n"),
+			},
+			response: `{
+				"name": "archive",
+				"content": "require \"fileinto\";\r\nfileinto \"Archive\";\r\n",
+				"is_valid": true,
+				"id": "6531b03e0bde8f333acea003",
+				"object": "sieve_script"
+			}`,
+			wantForm: url.Values{
+				"name":    {"archive"},
+				"content": {"require \"fileinto\";\r\nfileinto \"Archive\";\r\n"},
+			},
+			want: &SieveScript{
+				Name:    "archive",
+				Content: "require \"fileinto\";\r\nfileinto \"Archive\";\r\n",
+				IsValid: true,
+				Id:      "6531b03e0bde8f333acea003",
+				Object:  "sieve_script",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotForm url.Values
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				gotForm = r.PostForm
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got, _ := c.CreateSieveScript(tt.domain, tt.alias, tt.parameters)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantForm, gotForm); diff != "" {
+				t.Fatalf("form values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_UpdateSieveScript(t *testing.T) {
+	tests := []struct {
+		name       string
+		domain     string
+		alias      string
+		id         string
+		parameters SieveScriptParameters
+		response   string
+		wantPath   string
+		wantForm   url.Values
+		want       *SieveScript
+	}{
+		{
+			name:     "no data",
+			wantPath: "/v1/domains//aliases//sieve/",
+			wantForm: url.Values{},
+		},
+		{
+			name:   "ok",
+			domain: "stark.com",
+			alias:  "tony",
+			id:     "6531b03e0bde8f333acea003",
+			parameters: SieveScriptParameters{
+				Description: pointString("Archive everything"),
+			},
+			response: `{
+				"name": "archive",
+				"description": "Archive everything",
+				"id": "6531b03e0bde8f333acea003",
+				"object": "sieve_script"
+			}`,
+			wantPath: "/v1/domains/stark.com/aliases/tony/sieve/6531b03e0bde8f333acea003",
+			wantForm: url.Values{
+				"description": {"Archive everything"},
+			},
+			want: &SieveScript{
+				Name:        "archive",
+				Description: "Archive everything",
+				Id:          "6531b03e0bde8f333acea003",
+				Object:      "sieve_script",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			var gotForm url.Values
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				gotPath, gotForm = r.URL.Path, r.PostForm
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got, _ := c.UpdateSieveScript(tt.domain, tt.alias, tt.id, tt.parameters)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if gotPath != tt.wantPath {
+				t.Fatalf("unexpected path %s", gotPath)
+			}
+			if diff := cmp.Diff(tt.wantForm, gotForm); diff != "" {
+				t.Fatalf("form values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_ActivateSieveScript(t *testing.T) {
+	tests := []struct {
+		name       string
+		domain     string
+		alias      string
+		id         string
+		response   string
+		wantMethod string
+		wantPath   string
+		want       *SieveScript
+	}{
+		{
+			name:       "ok",
+			domain:     "stark.com",
+			alias:      "tony",
+			id:         "6531b03e0bde8f333acea003",
+			response:   `{"name": "archive", "is_active": true, "id": "6531b03e0bde8f333acea003"}`,
+			wantMethod: "POST",
+			wantPath:   "/v1/domains/stark.com/aliases/tony/sieve/6531b03e0bde8f333acea003/activate",
+			want: &SieveScript{
+				Name:     "archive",
+				IsActive: true,
+				Id:       "6531b03e0bde8f333acea003",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotMethod, gotPath string
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotMethod, gotPath = r.Method, r.URL.Path
+				fmt.Fprintf(w, tt.response)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got, _ := c.ActivateSieveScript(tt.domain, tt.alias, tt.id)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			if gotMethod != tt.wantMethod || gotPath != tt.wantPath {
+				t.Fatalf("unexpected request %s %s", gotMethod, gotPath)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteSieveScript(t *testing.T) {
+	type response struct {
+		code int
+		body string
+	}
+
+	tests := []struct {
+		name   string
+		domain string
+		alias  string
+		id     string
+		resp   response
+		want   error
+	}{
+		{
+			name: "ok",
+			resp: response{
+				code: http.StatusNoContent,
+			},
+		},
+		{
+			name: "not ok",
+			resp: response{
+				code: http.StatusInternalServerError,
+				body: "oh no",
+			},
+			want: &APIError{StatusCode: 500, Body: []byte("oh no")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.resp.code)
+				fmt.Fprintf(w, tt.resp.body)
+			}))
+			defer svr.Close()
+
+			c := NewClient(ClientOptions{
+				ApiUrl: svr.URL,
+			})
+
+			got := c.DeleteSieveScript(tt.domain, tt.alias, tt.id)
+			if diff := cmp.Diff(tt.want, got, cmp.Comparer(equateErrorMessage)); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,375 @@
+package forwardemail
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	VCardVersion3 = "3.0"
+	VCardVersion4 = "4.0"
+)
+
+// VCard renders the contact as a vCard of the given version, VCardVersion3
+// or VCardVersion4 (the default for any other value). Properties of Content
+// that Contact does not model, such as ORG or ADR, are kept when Content has
+// the same version.
+func (c Contact) VCard(version string) string {
+	if version != VCardVersion3 {
+		version = VCardVersion4
+	}
+
+	lines := []string{
+		"BEGIN:VCARD",
+		"VERSION:" + version,
+	}
+
+	if c.IsGroup && version == VCardVersion4 {
+		lines = append(lines, "KIND:group")
+	}
+	if c.IsGroup && version == VCardVersion3 {
+		lines = append(lines, "X-ADDRESSBOOKSERVER-KIND:group")
+	}
+
+	lines = append(lines, "FN:"+escapeText(c.FullName))
+
+	n, extra := c.vCardExtras(version)
+	if n == "" {
+		given, family := c.FullName, ""
+		if i := strings.LastIndex(c.FullName, " "); i >= 0 {
+			given, family = c.FullName[:i], c.FullName[i+1:]
+		}
+		n = "N:" + escapeText(family) + ";" + escapeText(given) + ";;;"
+	}
+	lines = append(lines, n)
+
+	for _, e := range c.Emails {
+		lines = append(lines, "EMAIL"+vCardTypeParam(e.Type)+":"+escapeText(e.Value))
+	}
+
+	for _, p := range c.PhoneNumbers {
+		if version == VCardVersion4 {
+			lines = append(lines, "TEL;VALUE=uri"+vCardTypeParam(p.Type)+":tel:"+strings.Join(strings.Fields(p.Value), ""))
+		} else {
+			lines = append(lines, "TEL"+vCardTypeParam(p.Type)+":"+escapeText(p.Value))
+		}
+	}
+
+	lines = append(lines, extra...)
+	lines = append(lines, "END:VCARD")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(foldContentLine(line))
+	}
+
+	return b.String()
+}
+
+// vCardExtras returns the properties of Content that are not rendered from
+// the Contact fields. The original N is returned too while FN is unchanged,
+// since it may hold more than the full name can express.
+func (c Contact) vCardExtras(version string) (string, []string) {
+	lines, err := readContentLines(strings.NewReader(c.Content))
+	if err != nil {
+		return "", nil
+	}
+
+	var n, fn, cardVersion string
+	var extra []string
+
+	for _, l := range lines {
+		name, _, value, ok := parseContentLine(l.text)
+		if !ok {
+			continue
+		}
+
+		switch name {
+		case "VERSION":
+			cardVersion = value
+		case "FN":
+			fn = unescapeText(value)
+		case "N":
+			n = l.text
+		case "BEGIN", "END", "EMAIL", "TEL", "KIND", "X-ADDRESSBOOKSERVER-KIND":
+		default:
+			extra = append(extra, l.text)
+		}
+	}
+
+	if cardVersion != version {
+		return "", nil
+	}
+	if fn != c.FullName {
+		n = ""
+	}
+
+	return n, extra
+}
+
+// WriteVCards writes the contacts as a single .vcf stream.
+func WriteVCards(w io.Writer, contacts []Contact, version string) error {
+	for _, c := range contacts {
+		if _, err := io.WriteString(w, c.VCard(version)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ParseVCards reads every vCard (3.0 or 4.0) from a .vcf stream. Each contact
+// keeps the unfolded card in Content, so properties that Contact does not
+// model survive a re-upload.
+func ParseVCards(r io.Reader) ([]Contact, error) {
+	lines, err := readContentLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var contacts []Contact
+	var current *Contact
+	var raw []string
+	var givenName, familyName string
+
+	for _, l := range lines {
+		name, params, value, ok := parseContentLine(l.text)
+		if !ok {
+			return nil, fmt.Errorf("vcard: line %d: invalid content line %q", l.number, l.text)
+		}
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VCARD"):
+			if current != nil {
+				return nil, fmt.Errorf("vcard: line %d: nested BEGIN:VCARD", l.number)
+			}
+			current = &Contact{}
+			raw = nil
+			givenName, familyName = "", ""
+		case current == nil:
+			return nil, fmt.Errorf("vcard: line %d: property outside of BEGIN:VCARD", l.number)
+		case name == "END" && strings.EqualFold(value, "VCARD"):
+			raw = append(raw, l.text)
+			if current.FullName == "" {
+				current.FullName = strings.TrimSpace(givenName + " " + familyName)
+			}
+			var b strings.Builder
+			for _, line := range raw {
+				b.WriteString(foldContentLine(line))
+			}
+			current.Content = b.String()
+			contacts = append(contacts, *current)
+			current = nil
+			continue
+		case name == "FN":
+			current.FullName = unescapeText(value)
+		case name == "N":
+			parts := splitValue(value, ';')
+			if len(parts) > 0 {
+				familyName = unescapeText(parts[0])
+			}
+			if len(parts) > 1 {
+				givenName = unescapeText(parts[1])
+			}
+		case name == "EMAIL":
+			current.Emails = append(current.Emails, ContactValue{
+				Value: unescapeText(value),
+				Type:  vCardType(params),
+			})
+		case name == "TEL":
+			current.PhoneNumbers = append(current.PhoneNumbers, ContactValue{
+				Value: strings.TrimPrefix(unescapeText(value), "tel:"),
+				Type:  vCardType(params),
+			})
+		case name == "KIND", name == "X-ADDRESSBOOKSERVER-KIND":
+			current.IsGroup = strings.EqualFold(value, "group")
+		}
+
+		raw = append(raw, l.text)
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("vcard: missing END:VCARD")
+	}
+
+	return contacts, nil
+}
+
+type contentLine struct {
+	number int
+	text   string
+}
+
+// readContentLines unfolds RFC 6350 / RFC 5545 content lines, where a line
+// starting with a space or tab continues the previous one.
+func readContentLines(r io.Reader) ([]contentLine, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	var lines []contentLine
+	number := 0
+
+	for scanner.Scan() {
+		number++
+		text := strings.TrimRight(scanner.Text(), "\r")
+
+		if (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1].text += text[1:]
+			continue
+		}
+		if text == "" {
+			continue
+		}
+
+		lines = append(lines, contentLine{number: number, text: text})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// parseContentLine splits "group.NAME;PARAM=a,b:value" into its upper-cased
+// name, parameters and raw value. Colons inside quoted parameters are kept.
+func parseContentLine(line string) (string, map[string][]string, string, bool) {
+	quoted := false
+	colon := -1
+
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		}
+		if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return "", nil, "", false
+	}
+
+	parts := splitValue(line[:colon], ';')
+	name := strings.ToUpper(parts[0])
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	params := map[string][]string{}
+	for _, p := range parts[1:] {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok {
+			// vCard 2.1 style bare type, such as "TEL;CELL:".
+			k, v = "TYPE", p
+		}
+		k = strings.ToUpper(k)
+		for _, vv := range splitValue(v, ',') {
+			params[k] = append(params[k], strings.Trim(vv, `"`))
+		}
+	}
+
+	return name, params, line[colon+1:], true
+}
+
+// splitValue splits on sep, ignoring escaped separators and separators
+// inside double quotes.
+func splitValue(s string, sep rune) []string {
+	var parts []string
+	var b strings.Builder
+	escaped, quoted := false, false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			b.WriteRune('\\')
+			b.WriteRune(r)
+			escaped = false
+			continue
+		case r == '\\':
+			escaped = true
+			continue
+		case r == '"':
+			quoted = !quoted
+		case r == sep && !quoted:
+			parts = append(parts, b.String())
+			b.Reset()
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return append(parts, b.String())
+}
+
+func vCardType(params map[string][]string) string {
+	var types []string
+
+	for _, t := range params["TYPE"] {
+		if t = strings.ToLower(t); t != "pref" && t != "internet" {
+			types = append(types, t)
+		}
+	}
+
+	return strings.Join(types, ",")
+}
+
+func vCardTypeParam(t string) string {
+	if t == "" {
+		return ""
+	}
+
+	return ";TYPE=" + t
+}
+
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+func unescapeText(s string) string {
+	var b strings.Builder
+	escaped := false
+
+	for _, r := range s {
+		if escaped {
+			switch r {
+			case 'n', 'N':
+				b.WriteRune('\n')
+			default:
+				b.WriteRune(r)
+			}
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// foldContentLine terminates a content line with CRLF, folding it so that no
+// physical line exceeds 75 octets without splitting a UTF-8 sequence.
+func foldContentLine(line string) string {
+	var b strings.Builder
+	width := 0
+
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+
+	b.WriteString("\r\n")
+
+	return b.String()
+}
@@ -0,0 +1,304 @@
+package forwardemail
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParseVCards(t *testing.T) {
+	tests := []struct {
+		name    string
+		vcf     string
+		want    []Contact
+		wantErr string
+	}{
+		{
+			name: "no data",
+		},
+		{
+			name: "version 3 and 4",
+			vcf: "BEGIN:VCARD\r\n" +
+				"VERSION:3.0\r\n" +
+				"N:Rhodes;James;;;\r\n" +
+				"FN:James Rhodes\r\n" +
+				"item1.EMAIL;TYPE=INTERNET,WORK,pref:james@rhodes.com\r\n" +
+				"TEL;TYPE=CELL:+1 555 555 0100\r\n" +
+				"NOTE:Call sign\\, War Machine\\nColonel\r\n" +
+				"END:VCARD\r\n" +
+				"BEGIN:VCARD\n" +
+				"VERSION:4.0\n" +
+				"FN:Pepper \n" +
+				" Potts\n" +
+				"EMAIL;TYPE=work:pepper@stark.com\n" +
+				"EMAIL:pepper@potts.com\n" +
+				"TEL;VALUE=uri;TYPE=\"voice,cell\":tel:+15555550101\n" +
+				"END:VCARD\n" +
+				"BEGIN:VCARD\n" +
+				"VERSION:4.0\n" +
+				"KIND:group\n" +
+				"N:Avengers;;;;\n" +
+				"END:VCARD\n",
+			want: []Contact{
+				{
+					FullName:     "James Rhodes",
+					Emails:       []ContactValue{{Value: "james@rhodes.com", Type: "work"}},
+					PhoneNumbers: []ContactValue{{Value: "+1 555 555 0100", Type: "cell"}},
+				},
+				{
+					FullName: "Pepper Potts",
+					Emails: []ContactValue{
+						{Value: "pepper@stark.com", Type: "work"},
+						{Value: "pepper@potts.com"},
+					},
+					PhoneNumbers: []ContactValue{{Value: "+15555550101", Type: "voice,cell"}},
+				},
+				{
+					FullName: "Avengers",
+					IsGroup:  true,
+				},
+			},
+		},
+		{
+			name:    "missing end",
+			vcf:     "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Tony Stark\r\n",
+			wantErr: "vcard: missing END:VCARD",
+		},
+		{
+			name:    "nested",
+			vcf:     "BEGIN:VCARD\r\nBEGIN:VCARD\r\n",
+			wantErr: "vcard: line 2: nested BEGIN:VCARD",
+		},
+		{
+			name:    "invalid line",
+			vcf:     "BEGIN:VCARD\r\nFN Tony Stark\r\nEND:VCARD\r\n",
+			wantErr: `vcard: line 2: invalid content line "FN Tony Stark"`,
+		},
+		{
+			name:    "outside of card",
+			vcf:     "FN:Tony Stark\r\n",
+			wantErr: "vcard: line 1: property outside of BEGIN:VCARD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseVCards(strings.NewReader(tt.vcf))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(Contact{}, "Content")); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+			for _, c := range got {
+				if !strings.HasPrefix(c.Content, "BEGIN:VCARD\r\n") || !strings.HasSuffix(c.Content, "END:VCARD\r\n") {
+					t.Fatalf("unexpected content %q", c.Content)
+				}
+			}
+		})
+	}
+}
+
+func TestParseVCards_KeepsUnknownProperties(t *testing.T) {
+	got, err := ParseVCards(strings.NewReader("BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Tony Stark\r\nORG:Stark Industries\r\nEND:VCARD\r\n"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	want := "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Tony Stark\r\nORG:Stark Industries\r\nEND:VCARD\r\n"
+	if diff := cmp.Diff(want, got[0].Content); diff != "" {
+		t.Fatalf("values are not the same %s", diff)
+	}
+}
+
+func TestContact_VCard(t *testing.T) {
+	contact := Contact{
+		FullName: "Anthony Edward Stark",
+		Emails: []ContactValue{
+			{Value: "tony@stark.com", Type: "work"},
+		},
+		PhoneNumbers: []ContactValue{
+			{Value: "+1 555 555 0199", Type: "cell"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		contact Contact
+		version string
+		want    string
+	}{
+		{
+			name:    "version 3",
+			contact: contact,
+			version: VCardVersion3,
+			want: "BEGIN:VCARD\r\n" +
+				"VERSION:3.0\r\n" +
+				"FN:Anthony Edward Stark\r\n" +
+				"N:Stark;Anthony Edward;;;\r\n" +
+				"EMAIL;TYPE=work:tony@stark.com\r\n" +
+				"TEL;TYPE=cell:+1 555 555 0199\r\n" +
+				"END:VCARD\r\n",
+		},
+		{
+			name:    "version 4",
+			contact: contact,
+			version: VCardVersion4,
+			want: "BEGIN:VCARD\r\n" +
+				"VERSION:4.0\r\n" +
+				"FN:Anthony Edward Stark\r\n" +
+				"N:Stark;Anthony Edward;;;\r\n" +
+				"EMAIL;TYPE=work:tony@stark.com\r\n" +
+				"TEL;VALUE=uri;TYPE=cell:tel:+15555550199\r\n" +
+				"END:VCARD\r\n",
+		},
+		{
+			name:    "escaping and folding",
+			contact: Contact{FullName: "Stark, Tony; the one with a very long name that definitely needs folding"},
+			version: VCardVersion4,
+			want: "BEGIN:VCARD\r\n" +
+				"VERSION:4.0\r\n" +
+				"FN:Stark\\, Tony\\; the one with a very long name that definitely needs foldi\r\n" +
+				" ng\r\n" +
+				"N:folding;Stark\\, Tony\\; the one with a very long name that definitely need\r\n" +
+				" s;;;\r\n" +
+				"END:VCARD\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.contact.VCard(tt.version)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestContact_VCardKeepsContent(t *testing.T) {
+	card := "BEGIN:VCARD\r\n" +
+		"VERSION:3.0\r\n" +
+		"FN:Tony Stark\r\n" +
+		"N:Stark;Tony;Edward;Mr.;\r\n" +
+		"ORG:Stark Industries\r\n" +
+		"item1.EMAIL;TYPE=work:tony@stark.com\r\n" +
+		"item1.X-ABLABEL:Lab\r\n" +
+		"ADR;TYPE=work:;;10880 Malibu Point;Malibu;CA;90265;USA\r\n" +
+		"NOTE:Allergic to strawberries\r\n" +
+		"END:VCARD\r\n"
+
+	contacts, err := ParseVCards(strings.NewReader(card))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		contact func(c Contact) Contact
+		version string
+		want    string
+	}{
+		{
+			name:    "unchanged",
+			contact: func(c Contact) Contact { return c },
+			version: VCardVersion3,
+			want: "BEGIN:VCARD\r\n" +
+				"VERSION:3.0\r\n" +
+				"FN:Tony Stark\r\n" +
+				"N:Stark;Tony;Edward;Mr.;\r\n" +
+				"EMAIL;TYPE=work:tony@stark.com\r\n" +
+				"ORG:Stark Industries\r\n" +
+				"item1.X-ABLABEL:Lab\r\n" +
+				"ADR;TYPE=work:;;10880 Malibu Point;Malibu;CA;90265;USA\r\n" +
+				"NOTE:Allergic to strawberries\r\n" +
+				"END:VCARD\r\n",
+		},
+		{
+			name: "modified",
+			contact: func(c Contact) Contact {
+				c.FullName = "Anthony Stark"
+				c.Emails = []ContactValue{{Value: "tony@avengers.com"}}
+				return c
+			},
+			version: VCardVersion3,
+			want: "BEGIN:VCARD\r\n" +
+				"VERSION:3.0\r\n" +
+				"FN:Anthony Stark\r\n" +
+				"N:Stark;Anthony;;;\r\n" +
+				"EMAIL:tony@avengers.com\r\n" +
+				"ORG:Stark Industries\r\n" +
+				"item1.X-ABLABEL:Lab\r\n" +
+				"ADR;TYPE=work:;;10880 Malibu Point;Malibu;CA;90265;USA\r\n" +
+				"NOTE:Allergic to strawberries\r\n" +
+				"END:VCARD\r\n",
+		},
+		{
+			name:    "other version",
+			contact: func(c Contact) Contact { return c },
+			version: VCardVersion4,
+			want: "BEGIN:VCARD\r\n" +
+				"VERSION:4.0\r\n" +
+				"FN:Tony Stark\r\n" +
+				"N:Stark;Tony;;;\r\n" +
+				"EMAIL;TYPE=work:tony@stark.com\r\n" +
+				"END:VCARD\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.contact(contacts[0]).VCard(tt.version)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
+
+func TestVCards_RoundTrip(t *testing.T) {
+	contacts := []Contact{
+		{
+			FullName:     "Тони Старк, Iron Man",
+			Emails:       []ContactValue{{Value: "tony@stark.com", Type: "work"}, {Value: "tony@home.com", Type: "home"}},
+			PhoneNumbers: []ContactValue{{Value: "+15555550199", Type: "cell"}},
+		},
+		{
+			FullName: "Avengers",
+			IsGroup:  true,
+		},
+	}
+
+	for _, version := range []string{VCardVersion3, VCardVersion4} {
+		t.Run(version, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteVCards(&buf, contacts, version); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			for _, line := range strings.Split(buf.String(), "\r\n") {
+				if len(line) > 75 {
+					t.Fatalf("line is too long: %q", line)
+				}
+			}
+
+			got, err := ParseVCards(&buf)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if diff := cmp.Diff(contacts, got, cmpopts.IgnoreFields(Contact{}, "Content")); diff != "" {
+				t.Fatalf("values are not the same %s", diff)
+			}
+		})
+	}
+}
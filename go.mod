@@ -1,5 +1,5 @@
 module github.com/abagayev/go-forwardemail
 
-go 1.21
+go 1.23
 
 require github.com/google/go-cmp v0.5.9